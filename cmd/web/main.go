@@ -1,22 +1,42 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	_ "time/tzdata"
 
 	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/core/avatar"
+	"github.com/joaovictorsl/go-backend-template/internal/core/export"
+	"github.com/joaovictorsl/go-backend-template/internal/core/flags"
+	orgservice "github.com/joaovictorsl/go-backend-template/internal/core/organization/service"
+	orgusecase "github.com/joaovictorsl/go-backend-template/internal/core/organization/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
 	userservice "github.com/joaovictorsl/go-backend-template/internal/core/user/service"
 	userusecase "github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/mail"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/blob"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/inmemory"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres"
+	"github.com/joaovictorsl/go-backend-template/internal/web/dpop"
 	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+	"github.com/joaovictorsl/go-backend-template/internal/web/pagination"
+	"github.com/joaovictorsl/go-backend-template/internal/web/revocation"
 	"github.com/joaovictorsl/go-backend-template/internal/web/server"
 )
 
 func main() {
 	cfg := config.New()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	logger := slog.New(
 		slog.NewTextHandler(
 			os.Stdout,
@@ -35,7 +55,12 @@ func main() {
 	defer db.Close()
 
 	oauthStore := inmemory.New()
-	jwtManager, err := jwt.NewTokenManager(cfg.JwtSecret, cfg.AccessTokenTTL)
+	jwtManager, err := jwt.NewTokenManager(
+		cfg.JwtSecret,
+		cfg.AccessTokenTTL,
+		cfg.TokenIssuer,
+		cfg.TokenAudience,
+	)
 	if err != nil {
 		slog.Error(
 			"creating new jwt TokenManager instance",
@@ -46,9 +71,87 @@ func main() {
 
 	refreshTokenRepository := postgres.NewRefreshTokenRepository(db)
 
+	revocations := revocation.New(postgres.NewRevocationRepository(db), cfg.AccessTokenTTL)
+	if err := revocations.Load(ctx); err != nil {
+		slog.Error(
+			"loading token revocations",
+			slog.Any("error", err),
+		)
+		return
+	}
+	go revocations.Run(ctx)
+
+	blobs, err := blob.NewLocal(cfg.BlobLocalDir)
+	if err != nil {
+		slog.Error(
+			"opening blob storage",
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	avatars := avatar.New(postgres.NewAvatarRepository(db), blobs)
+
 	userRepository := postgres.NewUserRepository(db)
-	userService := userservice.New(userRepository)
-	userUseCase := userusecase.New(userService)
+	mailer := mail.NewLogMailer()
+	userService := userservice.New(
+		userRepository,
+		revocations,
+		avatars,
+		mailer,
+		userservice.Policy{
+			StatusCacheTTL:      cfg.UserStatusCacheTTL,
+			DeletionGracePeriod: cfg.UserDeletionGracePeriod,
+			EmailChangeTTL:      cfg.EmailChangeTTL,
+			EmailConfirmURL:     cfg.EmailConfirmURL,
+		},
+	)
+	// Background jobs work across tenants and must opt out of row level
+	// security explicitly.
+	systemCtx := policy.AsSystem(ctx)
+	go userService.RunPurge(systemCtx, cfg.UserPurgeInterval)
+	policies := policy.New(
+		cfg.PolicyExplain,
+		slices.Concat(userusecase.Policies(), orgusecase.Policies())...,
+	)
+	userUseCase := userusecase.New(userService, policies)
+	adminUseCase := userusecase.NewAdmin(userService, policies)
+
+	orgRepository := postgres.NewOrganizationRepository(db)
+	orgService := orgservice.New(
+		orgRepository,
+		orgRepository,
+		userRepository,
+		mailer,
+		orgservice.InvitationPolicy{
+			TTL:       cfg.InvitationTTL,
+			AcceptURL: cfg.InvitationAcceptURL,
+		},
+	)
+	plans := plan.New(postgres.NewPlanRepository(db), cfg.DefaultPlan)
+	orgUseCase := orgusecase.New(orgService, policies, plans)
+
+	userSettings := settings.New(postgres.NewSettingsRepository(db), settings.NewDefaultRegistry())
+
+	exports := export.New(
+		postgres.NewDataExportRepository(db),
+		blobs,
+		export.NewSigner(cfg.ExportSigningKey, cfg.ExportLinkTTL, cfg.ExportDownloadURL),
+		export.Policy{
+			Retention:    cfg.ExportRetention,
+			PollInterval: cfg.ExportPollInterval,
+		},
+		plans,
+	)
+	exports.Register("user", export.UserSource(userService))
+	exports.Register("linked_accounts", export.LinkedAccountSource(userRepository))
+	exports.Register("sessions", export.SessionSource(userRepository))
+	exports.Register("memberships", export.MembershipSource(orgRepository))
+	exports.Register("settings", export.SettingsSource(userSettings))
+	go exports.Run(systemCtx)
+
+	proofValidator := dpop.NewValidator(cfg.DPoPProofMaxAge, cfg.TrustedProxies, postgres.NewDPoPProofRepository(db))
+	go proofValidator.Run(ctx)
 
 	app := &server.Server{
 		Config:            cfg,
@@ -56,7 +159,20 @@ func main() {
 		RefreshTokenStore: refreshTokenRepository,
 		UserStore:         userRepository,
 		JwtManager:        jwtManager,
+		Revocations:       revocations,
+		ProofValidator:    proofValidator,
+		Permissions:       rbac.NewResolver(postgres.NewRBACRepository(db), cfg.PermissionsCacheTTL),
+		Flags:             flags.New(postgres.NewFlagRepository(db), cfg.Env, cfg.FlagsCacheTTL),
+		OrganizationStore: orgRepository,
+		UserService:       userService,
 		UserUseCase:       userUseCase,
+		AdminUseCase:      adminUseCase,
+		OrgUseCase:        orgUseCase,
+		Plans:             plans,
+		Exports:           exports,
+		Avatars:           avatars,
+		Settings:          userSettings,
+		Cursors:           pagination.NewCodec(cfg.CursorSigningKey),
 	}
 	app.SetupRoutes()
 
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/netip"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -10,21 +17,70 @@ import (
 
 const (
 	envProd = "prod"
+
+	sessionLimitReject      = "reject"
+	sessionLimitEvictOldest = "evict_oldest"
+
+	cookiePrefixHost   = "__Host-"
+	cookiePrefixSecure = "__Secure-"
 )
 
 type Config struct {
-	DatabaseUrl             string
-	GoogleClientId          string
-	GoogleClientSecret      string
-	GoogleClientRedirectUrl string
-	JwtSecret               string
-	Env                     string
-	Port                    uint
-	RequestTimeout          time.Duration
-	ShutdownTimeout         time.Duration
-	LogLevel                slog.Level
-	AccessTokenTTL          time.Duration
-	RefreshTokenTTL         time.Duration
+	DatabaseUrl              string
+	GoogleClientId           string
+	GoogleClientSecret       string
+	GoogleClientRedirectUrl  string
+	JwtSecret                string
+	Env                      string
+	Port                     uint
+	RequestTimeout           time.Duration
+	ShutdownTimeout          time.Duration
+	LogLevel                 slog.Level
+	AccessTokenTTL           time.Duration
+	SessionIdleTimeout       time.Duration
+	SessionAbsoluteLifetime  time.Duration
+	SessionMaxPerUser        int
+	SessionLimitPolicy       string
+	TokenIssuer              string
+	TokenAudience            string
+	TransparentTokenRenewal  bool
+	ReauthenticationMaxAge   time.Duration
+	Cookies                  CookieConfig
+	MachineClients           map[string]string
+	DPoPProofMaxAge          time.Duration
+	PermissionsCacheTTL      time.Duration
+	PolicyExplain            bool
+	InvitationTTL            time.Duration
+	InvitationAcceptURL      string
+	DatabaseRowLevelSecurity bool
+	UserStatusCacheTTL       time.Duration
+	FlagsCacheTTL            time.Duration
+	DefaultPlan              string
+	UserDeletionGracePeriod  time.Duration
+	UserPurgeInterval        time.Duration
+	BlobLocalDir             string
+	ExportRetention          time.Duration
+	ExportLinkTTL            time.Duration
+	ExportPollInterval       time.Duration
+	ExportDownloadURL        string
+	EmailChangeTTL           time.Duration
+	EmailConfirmURL          string
+	AvatarMaxUploadBytes     int64
+	AvatarCacheMaxAge        time.Duration
+	TrustedProxies           []netip.Prefix
+	ExportSigningKey         []byte
+	RefreshTokenGracePeriod  time.Duration
+	CursorSigningKey         []byte
+}
+
+type CookieConfig struct {
+	Secure           bool
+	Domain           string
+	SameSite         http.SameSite
+	Prefix           string
+	AccessTokenName  string
+	RefreshTokenName string
+	CSRFName         string
 }
 
 func init() {
@@ -39,6 +95,8 @@ func New() *Config {
 		panic(err)
 	}
 
+	env := viper.GetString("env")
+
 	return &Config{
 		viper.GetString("database_url"),
 		viper.GetString("google_client_id"),
@@ -46,13 +104,46 @@ func New() *Config {
 		viper.GetString("google_client_redirect_url"),
 		viper.GetString("jwt_secret"),
 
-		viper.GetString("env"),
+		env,
 		viper.GetUint("port"),
 		viper.GetDuration("timeouts.request"),
 		viper.GetDuration("timeouts.shutdown"),
 		parseLogLevel(viper.GetString("log.level")),
 		viper.GetDuration("token.access_ttl"),
-		viper.GetDuration("token.refresh_ttl"),
+		getSessionDuration(env, "idle_timeout"),
+		getSessionDuration(env, "absolute_lifetime"),
+		viper.GetInt("session.max_per_user"),
+		parseSessionLimitPolicy(viper.GetString("session.limit_policy")),
+		viper.GetString("token.issuer"),
+		viper.GetString("token.audience"),
+		viper.GetBool("auth.transparent_token_renewal"),
+		viper.GetDuration("auth.reauthentication_max_age"),
+		parseCookieConfig(),
+		parseMachineClients(),
+		viper.GetDuration("dpop.proof_max_age"),
+		viper.GetDuration("rbac.cache_ttl"),
+		viper.GetBool("policy.explain"),
+		viper.GetDuration("invitations.ttl"),
+		viper.GetString("invitations.accept_url"),
+		viper.GetBool("database.row_level_security"),
+		viper.GetDuration("users.status_cache_ttl"),
+		viper.GetDuration("flags.cache_ttl"),
+		viper.GetString("plans.default"),
+		viper.GetDuration("users.deletion_grace_period"),
+		viper.GetDuration("users.purge_interval"),
+		viper.GetString("blob.local_dir"),
+		viper.GetDuration("exports.retention"),
+		viper.GetDuration("exports.link_ttl"),
+		viper.GetDuration("exports.poll_interval"),
+		viper.GetString("exports.download_url"),
+		viper.GetDuration("users.email_change_ttl"),
+		viper.GetString("users.email_confirm_url"),
+		viper.GetInt64("avatars.max_upload_bytes"),
+		viper.GetDuration("avatars.cache_max_age"),
+		parseTrustedProxies(viper.GetStringSlice("http.trusted_proxies")),
+		getSigningKey("export_signing_key", "export download links"),
+		viper.GetDuration("session.rotation_grace_period"),
+		getSigningKey("cursor_signing_key", "pagination cursors"),
 	}
 }
 
@@ -62,6 +153,8 @@ func setConfigDefaults() {
 	viper.MustBindEnv("google_client_secret")
 	viper.MustBindEnv("google_client_redirect_url")
 	viper.MustBindEnv("jwt_secret")
+	viper.MustBindEnv("export_signing_key")
+	viper.MustBindEnv("cursor_signing_key")
 
 	viper.SetDefault("env", envProd)
 	viper.SetDefault("port", 8000)
@@ -69,7 +162,162 @@ func setConfigDefaults() {
 	viper.SetDefault("timeouts.shutdown", "1m")
 	viper.SetDefault("log.level", "debug")
 	viper.SetDefault("token.access_ttl", "10m")
-	viper.SetDefault("token.refresh_ttl", "168h")
+	viper.SetDefault("session.idle_timeout", "168h")
+	viper.SetDefault("session.absolute_lifetime", "720h")
+	viper.SetDefault("session.max_per_user", 0)
+	viper.SetDefault("session.limit_policy", sessionLimitEvictOldest)
+	viper.SetDefault("session.rotation_grace_period", "10s")
+	viper.SetDefault("token.issuer", "go-backend-template")
+	viper.SetDefault("token.audience", "go-backend-template")
+	viper.SetDefault("auth.transparent_token_renewal", false)
+	viper.SetDefault("auth.reauthentication_max_age", "10m")
+	viper.SetDefault("cookies.secure", true)
+	viper.SetDefault("cookies.domain", "")
+	viper.SetDefault("cookies.same_site", "lax")
+	viper.SetDefault("cookies.prefix", "")
+	viper.SetDefault("cookies.names.access_token", "atok")
+	viper.SetDefault("cookies.names.refresh_token", "rtok")
+	viper.SetDefault("cookies.names.csrf", "csrf_token")
+	viper.SetDefault("dpop.proof_max_age", "1m")
+	viper.SetDefault("rbac.cache_ttl", "1m")
+	viper.SetDefault("policy.explain", false)
+	viper.SetDefault("invitations.ttl", "168h")
+	viper.SetDefault("invitations.accept_url", "http://localhost:8000/invitations/accept")
+	viper.SetDefault("database.row_level_security", false)
+	viper.SetDefault("users.status_cache_ttl", "30s")
+	viper.SetDefault("flags.cache_ttl", "30s")
+	viper.SetDefault("plans.default", "free")
+	viper.SetDefault("users.deletion_grace_period", "720h")
+	viper.SetDefault("users.purge_interval", "1h")
+	viper.SetDefault("blob.local_dir", "data/blobs")
+	viper.SetDefault("exports.retention", "168h")
+	viper.SetDefault("exports.link_ttl", "15m")
+	viper.SetDefault("exports.poll_interval", "30s")
+	viper.SetDefault("exports.download_url", "http://localhost:8000/exports")
+	viper.SetDefault("users.email_change_ttl", "24h")
+	viper.SetDefault("users.email_confirm_url", "http://localhost:8000/users/me/email/confirm")
+	viper.SetDefault("avatars.max_upload_bytes", 5<<20)
+	viper.SetDefault("avatars.cache_max_age", "24h")
+	viper.SetDefault("http.trusted_proxies", []string{})
+}
+
+// getSigningKey returns the key configured under name or, when it is not set,
+// one derived from the jwt secret for purpose, so no two features ever sign
+// with the same key.
+func getSigningKey(name, purpose string) []byte {
+	if key := viper.GetString(name); key != "" {
+		return []byte(key)
+	}
+
+	key, err := hkdf.Key(sha256.New, []byte(viper.GetString("jwt_secret")), nil, purpose, sha256.Size)
+	if err != nil {
+		panic(fmt.Errorf("deriving %s: %w", name, err))
+	}
+	return key
+}
+
+// parseTrustedProxies accepts single addresses or CIDR ranges.
+func parseTrustedProxies(values []string) []netip.Prefix {
+	proxies := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			panic(fmt.Errorf("invalid trusted proxy %q: must be an ip address or cidr", v))
+		}
+		proxies = append(proxies, p.Masked())
+	}
+	return proxies
+}
+
+// parseMachineClients reads machine_clients as a list rather than a map
+// because viper lowercases map keys and client ids are case sensitive.
+func parseMachineClients() map[string]string {
+	var entries []struct {
+		ID     string `mapstructure:"id"`
+		Secret string `mapstructure:"secret"`
+	}
+	if err := viper.UnmarshalKey("machine_clients", &entries); err != nil {
+		panic(fmt.Errorf("invalid machine_clients: %w", err))
+	}
+
+	clients := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.ID == "" || e.Secret == "" {
+			panic(errors.New("invalid machine_clients: every client needs an id and a secret"))
+		}
+		if _, ok := clients[e.ID]; ok {
+			panic(fmt.Errorf("invalid machine_clients: duplicate client id %q", e.ID))
+		}
+		clients[e.ID] = e.Secret
+	}
+	return clients
+}
+
+func getSessionDuration(env string, key string) time.Duration {
+	envKey := fmt.Sprintf("session.policies.%s.%s", env, key)
+	if viper.IsSet(envKey) {
+		return viper.GetDuration(envKey)
+	}
+	return viper.GetDuration("session." + key)
+}
+
+func parseSessionLimitPolicy(s string) string {
+	if s != sessionLimitReject && s != sessionLimitEvictOldest {
+		panic(fmt.Errorf("invalid session limit_policy %q: must be %s or %s", s, sessionLimitReject, sessionLimitEvictOldest))
+	}
+	return s
+}
+
+func parseCookieConfig() CookieConfig {
+	cfg := CookieConfig{
+		Secure:           viper.GetBool("cookies.secure"),
+		Domain:           viper.GetString("cookies.domain"),
+		SameSite:         parseSameSite(viper.GetString("cookies.same_site")),
+		Prefix:           viper.GetString("cookies.prefix"),
+		AccessTokenName:  viper.GetString("cookies.names.access_token"),
+		RefreshTokenName: viper.GetString("cookies.names.refresh_token"),
+		CSRFName:         viper.GetString("cookies.names.csrf"),
+	}
+
+	switch cfg.Prefix {
+	case "":
+	case cookiePrefixHost:
+		if !cfg.Secure || cfg.Domain != "" {
+			panic(fmt.Errorf("cookie prefix %s requires secure cookies without a domain", cfg.Prefix))
+		}
+	case cookiePrefixSecure:
+		if !cfg.Secure {
+			panic(fmt.Errorf("cookie prefix %s requires secure cookies", cfg.Prefix))
+		}
+	default:
+		panic(fmt.Errorf("invalid cookie prefix %q: must be empty, %s or %s", cfg.Prefix, cookiePrefixHost, cookiePrefixSecure))
+	}
+
+	if cfg.SameSite == http.SameSiteNoneMode && !cfg.Secure {
+		panic(errors.New("cookie same_site none requires secure cookies"))
+	}
+
+	return cfg
+}
+
+func parseSameSite(s string) http.SameSite {
+	switch strings.ToLower(s) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	case "default":
+		return http.SameSiteDefaultMode
+	default:
+		panic(fmt.Errorf("invalid cookie same_site %q", s))
+	}
 }
 
 func parseLogLevel(s string) slog.Level {
@@ -0,0 +1,107 @@
+package config_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMachineClients(t *testing.T) {
+	t.Cleanup(func() { viper.Set("machine_clients", nil) })
+
+	tests := []struct {
+		name    string
+		clients any
+		want    map[string]string
+		panics  bool
+	}{
+		{
+			"should keep the case of client ids",
+			[]map[string]any{{"id": "Billing-Service", "secret": "s3cret"}},
+			map[string]string{"Billing-Service": "s3cret"},
+			false,
+		},
+		{
+			"should default to no clients",
+			nil,
+			map[string]string{},
+			false,
+		},
+		{
+			"should reject client without a secret",
+			[]map[string]any{{"id": "billing"}},
+			nil,
+			true,
+		},
+		{
+			"should reject duplicate client ids",
+			[]map[string]any{{"id": "billing", "secret": "a"}, {"id": "billing", "secret": "b"}},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("machine_clients", tt.clients)
+
+			if tt.panics {
+				assert.Panics(t, func() { config.New() })
+				return
+			}
+			assert.Equal(t, tt.want, config.New().MachineClients)
+		})
+	}
+}
+
+func TestCookieConfig(t *testing.T) {
+	keys := []string{"cookies.secure", "cookies.domain", "cookies.same_site", "cookies.prefix"}
+	t.Cleanup(func() {
+		for _, key := range keys {
+			viper.Set(key, nil)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		secure   bool
+		domain   string
+		sameSite string
+		prefix   string
+		want     http.SameSite
+		panics   bool
+	}{
+		{"should parse lax same site", false, "", "lax", "", http.SameSiteLaxMode, false},
+		{"should parse same site case insensitively", true, "", "Strict", "", http.SameSiteStrictMode, false},
+		{"should allow none same site on secure cookies", true, "", "none", "", http.SameSiteNoneMode, false},
+		{"should parse default same site", false, "", "default", "", http.SameSiteDefaultMode, false},
+		{"should reject none same site on insecure cookies", false, "", "none", "", 0, true},
+		{"should reject unknown same site", true, "", "relaxed", "", 0, true},
+		{"should allow host prefix on secure cookies without a domain", true, "", "lax", "__Host-", http.SameSiteLaxMode, false},
+		{"should reject host prefix on insecure cookies", false, "", "lax", "__Host-", 0, true},
+		{"should reject host prefix with a domain", true, "example.com", "lax", "__Host-", 0, true},
+		{"should allow secure prefix with a domain", true, "example.com", "lax", "__Secure-", http.SameSiteLaxMode, false},
+		{"should reject secure prefix on insecure cookies", false, "", "lax", "__Secure-", 0, true},
+		{"should reject unknown prefix", true, "", "lax", "__Other-", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("cookies.secure", tt.secure)
+			viper.Set("cookies.domain", tt.domain)
+			viper.Set("cookies.same_site", tt.sameSite)
+			viper.Set("cookies.prefix", tt.prefix)
+
+			if tt.panics {
+				assert.Panics(t, func() { config.New() })
+				return
+			}
+			cookies := config.New().Cookies
+			assert.Equal(t, tt.want, cookies.SameSite)
+			assert.Equal(t, tt.secure, cookies.Secure)
+			assert.Equal(t, tt.domain, cookies.Domain)
+			assert.Equal(t, tt.prefix, cookies.Prefix)
+		})
+	}
+}
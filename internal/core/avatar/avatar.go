@@ -0,0 +1,146 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+)
+
+const (
+	DefaultSize = 128
+)
+
+var (
+	ErrInvalidSize = fmt.Errorf("%w: invalid avatar size", core.ErrInvalid)
+)
+
+type Store interface {
+	Get(ctx context.Context, userId uuid.UUID) (entity.Avatar, error)
+	// SetVersion points the user at a new set of avatar blobs and returns
+	// the version it replaced. An empty version removes the avatar.
+	SetVersion(ctx context.Context, userId uuid.UUID, version string) (previous string, err error)
+	SetProviderPicture(ctx context.Context, provider, providerUserId, pictureURL string) error
+}
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Service struct {
+	store Store
+	blobs BlobStore
+}
+
+func New(store Store, blobs BlobStore) *Service {
+	return &Service{
+		store: store,
+		blobs: blobs,
+	}
+}
+
+func (s *Service) Get(ctx context.Context, userId uuid.UUID) (entity.Avatar, error) {
+	return s.store.Get(ctx, userId)
+}
+
+// Upload stores every size of the image under a new version before
+// switching the user over to it, so readers never see a partial set.
+func (s *Service) Upload(ctx context.Context, userId uuid.UUID, data []byte) (string, error) {
+	images, err := Process(data)
+	if err != nil {
+		return "", err
+	}
+
+	id, _ := uuid.NewV7()
+	version := fmt.Sprintf("%x", id[:])
+	for _, size := range Sizes {
+		if err := s.blobs.Put(ctx, blobKey(userId, version, size), bytes.NewReader(images[size])); err != nil {
+			s.deleteVersion(ctx, userId, version)
+			return "", fmt.Errorf("storing %dpx avatar: %w", size, err)
+		}
+	}
+
+	previous, err := s.store.SetVersion(ctx, userId, version)
+	if err != nil {
+		s.deleteVersion(ctx, userId, version)
+		return "", err
+	}
+
+	s.deleteVersion(ctx, userId, previous)
+	return version, nil
+}
+
+func (s *Service) Remove(ctx context.Context, userId uuid.UUID) error {
+	previous, err := s.store.SetVersion(ctx, userId, "")
+	if err != nil {
+		return err
+	}
+
+	s.deleteVersion(ctx, userId, previous)
+	return nil
+}
+
+func (s *Service) Open(ctx context.Context, userId uuid.UUID, version string, size int) (io.ReadSeekCloser, error) {
+	return s.blobs.Open(ctx, blobKey(userId, version, size))
+}
+
+// RecordProviderPicture remembers the picture an identity provider reported
+// for the account, used when the user has not uploaded an avatar. Only
+// https URLs are kept since they are handed to browsers as redirects.
+func (s *Service) RecordProviderPicture(ctx context.Context, provider, providerUserId, pictureURL string) error {
+	if pictureURL != "" {
+		u, err := url.Parse(pictureURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			pictureURL = ""
+		}
+	}
+	return s.store.SetProviderPicture(ctx, provider, providerUserId, pictureURL)
+}
+
+// DeletePurged removes the blobs of the avatar of an account that was hard
+// deleted, whose row no longer points at them.
+func (s *Service) DeletePurged(ctx context.Context, userId uuid.UUID, version string) {
+	s.deleteVersion(ctx, userId, version)
+}
+
+// FitSize returns the smallest stored size that is at least requested, or
+// the largest one when requested exceeds them all.
+func FitSize(requested int) (int, error) {
+	if requested <= 0 {
+		return 0, ErrInvalidSize
+	}
+	for _, size := range Sizes {
+		if size >= requested {
+			return size, nil
+		}
+	}
+	return Sizes[len(Sizes)-1], nil
+}
+
+func (s *Service) deleteVersion(ctx context.Context, userId uuid.UUID, version string) {
+	if version == "" {
+		return
+	}
+	for _, size := range Sizes {
+		if err := s.blobs.Delete(ctx, blobKey(userId, version, size)); err != nil {
+			slog.Error(
+				"deleting avatar blob",
+				slog.Any("error", err),
+				slog.String("user_id", userId.String()),
+				slog.String("version", version),
+			)
+		}
+	}
+}
+
+func blobKey(userId uuid.UUID, version string, size int) string {
+	return fmt.Sprintf("avatars/%s/%s/%d.jpg", userId, version, size)
+}
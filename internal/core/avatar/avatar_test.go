@@ -0,0 +1,171 @@
+package avatar_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/avatar"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	avatar  entity.Avatar
+	picture string
+}
+
+func (s *fakeStore) Get(ctx context.Context, userId uuid.UUID) (entity.Avatar, error) {
+	return s.avatar, nil
+}
+
+func (s *fakeStore) SetVersion(ctx context.Context, userId uuid.UUID, version string) (string, error) {
+	previous := s.avatar.Version
+	s.avatar.Version = version
+	return previous, nil
+}
+
+func (s *fakeStore) SetProviderPicture(ctx context.Context, provider, providerUserId, pictureURL string) error {
+	s.picture = pictureURL
+	return nil
+}
+
+type fakeBlobs struct {
+	blobs map[string][]byte
+}
+
+func (b *fakeBlobs) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	b.blobs[key] = data
+	return err
+}
+
+func (b *fakeBlobs) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	return nopCloser{bytes.NewReader(b.blobs[key])}, nil
+}
+
+func (b *fakeBlobs) Delete(ctx context.Context, key string) error {
+	delete(b.blobs, key)
+	return nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+func keysWith(blobs map[string][]byte, version string) int {
+	n := 0
+	for key := range blobs {
+		if strings.Contains(key, "/"+version+"/") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestUploadReplacesPreviousVersion(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	store := &fakeStore{}
+	blobs := &fakeBlobs{blobs: make(map[string][]byte)}
+	s := avatar.New(store, blobs)
+
+	first, err := s.Upload(ctx, userId, encodePNG(t, halves(20, 20)))
+	require.NoError(t, err)
+	assert.Equal(t, len(avatar.Sizes), keysWith(blobs.blobs, first))
+
+	second, err := s.Upload(ctx, userId, encodePNG(t, halves(20, 20)))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, second, store.avatar.Version)
+	assert.Zero(t, keysWith(blobs.blobs, first))
+	assert.Equal(t, len(avatar.Sizes), keysWith(blobs.blobs, second))
+
+	img, err := s.Open(ctx, userId, second, 128)
+	require.NoError(t, err)
+	defer img.Close()
+	cfg, _, err := image.DecodeConfig(img)
+	require.NoError(t, err)
+	assert.Equal(t, 128, cfg.Width)
+}
+
+func TestUploadKeepsCurrentAvatarOnInvalidImage(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{avatar: entity.Avatar{Version: "current"}}
+	blobs := &fakeBlobs{blobs: make(map[string][]byte)}
+	s := avatar.New(store, blobs)
+
+	_, err := s.Upload(ctx, uuid.New(), []byte("not an image"))
+	assert.ErrorIs(t, err, avatar.ErrUnsupportedImage)
+	assert.Equal(t, "current", store.avatar.Version)
+	assert.Empty(t, blobs.blobs)
+}
+
+func TestRemoveDeletesBlobs(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	store := &fakeStore{}
+	blobs := &fakeBlobs{blobs: make(map[string][]byte)}
+	s := avatar.New(store, blobs)
+
+	_, err := s.Upload(ctx, userId, encodePNG(t, halves(20, 20)))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Remove(ctx, userId))
+	assert.Empty(t, store.avatar.Version)
+	assert.Empty(t, blobs.blobs)
+}
+
+func TestRecordProviderPicture(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"should keep https url", "https://lh3.googleusercontent.com/a/photo", "https://lh3.googleusercontent.com/a/photo"},
+		{"should drop http url", "http://example.com/photo", ""},
+		{"should drop javascript url", "javascript:alert(1)", ""},
+		{"should clear with empty url", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{picture: "stale"}
+			s := avatar.New(store, &fakeBlobs{})
+
+			require.NoError(t, s.RecordProviderPicture(context.Background(), "google", "1", tt.url))
+			assert.Equal(t, tt.want, store.picture)
+		})
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		requested int
+		want      int
+		wantErr   bool
+	}{
+		{0, 0, true},
+		{-1, 0, true},
+		{1, 64, false},
+		{64, 64, false},
+		{65, 128, false},
+		{512, 512, false},
+		{4096, 512, false},
+	}
+	for _, tt := range tests {
+		size, err := avatar.FitSize(tt.requested)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, avatar.ErrInvalidSize)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, size, "requested %d", tt.requested)
+	}
+}
@@ -0,0 +1,285 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"slices"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+const (
+	// MaxPixels bounds the decoded size of an upload so a small file cannot
+	// expand into a huge bitmap.
+	MaxPixels = 16_000_000
+
+	jpegQuality = 85
+)
+
+var (
+	Sizes = []int{64, 128, 256, 512}
+
+	ErrUnsupportedImage = fmt.Errorf("%w: image must be a jpeg, png or gif", core.ErrInvalid)
+	ErrImageTooLarge    = fmt.Errorf("%w: image dimensions are too large", core.ErrInvalid)
+)
+
+// Process decodes an upload, applies its EXIF orientation, crops it to a
+// centered square and encodes it as JPEG in every size of Sizes. Encoding
+// from pixels drops any metadata the original carried.
+func Process(data []byte) (map[int][]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxPixels/cfg.Height {
+		return nil, ErrImageTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	// Only the largest size is sampled from the decoded image, the others
+	// are scaled down from it.
+	largest := slices.Max(Sizes)
+	base := sample(img, orientation, largest)
+
+	out := make(map[int][]byte, len(Sizes))
+	for _, size := range Sizes {
+		dst := base
+		if size != largest {
+			dst = resize(base, size)
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("encoding %dpx avatar: %w", size, err)
+		}
+		out[size] = buf.Bytes()
+	}
+	return out, nil
+}
+
+// sample orients, crops to a centered square and scales img to size in a
+// single pass over the decoded pixels, averaging the source pixels each
+// destination pixel covers. Transparent pixels are flattened onto white,
+// since JPEG has no alpha.
+func sample(img image.Image, orientation int, size int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if orientation < 1 || orientation > 8 {
+		orientation = 1
+	}
+
+	// Width and height once oriented.
+	ow, oh := w, h
+	if orientation >= 5 {
+		ow, oh = h, w
+	}
+	side := min(ow, oh)
+	ox0, oy0 := (ow-side)/2, (oh-side)/2
+
+	at := pixelReader(img)
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := range size {
+		sy0, sy1 := span(y, size, side)
+		for x := range size {
+			sx0, sx1 := span(x, size, side)
+
+			var r, g, bl, count uint32
+			for oy := oy0 + sy0; oy < oy0+sy1; oy++ {
+				for ox := ox0 + sx0; ox < ox0+sx1; ox++ {
+					px, py := unorient(ox, oy, w, h, orientation)
+					pr, pg, pb, pa := at(b.Min.X+px, b.Min.Y+py)
+					r += pr + 0xFF - pa
+					g += pg + 0xFF - pa
+					bl += pb + 0xFF - pa
+					count++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(bl / count)
+			dst.Pix[i+3] = 0xFF
+		}
+	}
+	return dst
+}
+
+// unorient maps a pixel of the oriented image back to the w by h source,
+// undoing the rotation and flip of an EXIF orientation value.
+func unorient(x, y, w, h, orientation int) (int, int) {
+	switch orientation {
+	case 2:
+		return w - 1 - x, y
+	case 3:
+		return w - 1 - x, h - 1 - y
+	case 4:
+		return x, h - 1 - y
+	case 5:
+		return y, x
+	case 6:
+		return y, h - 1 - x
+	case 7:
+		return w - 1 - y, h - 1 - x
+	case 8:
+		return w - 1 - y, x
+	default:
+		return x, y
+	}
+}
+
+// pixelReader returns a function reading alpha premultiplied 8 bit colors
+// from img, with fast paths for what the jpeg, png and gif decoders return.
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch img := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			yi, ci := img.YOffset(x, y), img.COffset(x, y)
+			r, g, b := color.YCbCrToRGB(img.Y[yi], img.Cb[ci], img.Cr[ci])
+			return uint32(r), uint32(g), uint32(b), 0xFF
+		}
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			v := uint32(img.Pix[img.PixOffset(x, y)])
+			return v, v, v, 0xFF
+		}
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			p := img.Pix[img.PixOffset(x, y):]
+			return uint32(p[0]), uint32(p[1]), uint32(p[2]), uint32(p[3])
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			p := img.Pix[img.PixOffset(x, y):]
+			a := uint32(p[3])
+			return uint32(p[0]) * a / 0xFF, uint32(p[1]) * a / 0xFF, uint32(p[2]) * a / 0xFF, a
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			r, g, b, a := img.At(x, y).RGBA()
+			return r >> 8, g >> 8, b >> 8, a >> 8
+		}
+	}
+}
+
+// resize scales a square image by averaging the source pixels each
+// destination pixel covers.
+func resize(src *image.RGBA, size int) *image.RGBA {
+	n := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := range size {
+		sy0, sy1 := span(y, size, n)
+		for x := range size {
+			sx0, sx1 := span(x, size, n)
+
+			var r, g, b, a, count uint64
+			for sy := sy0; sy < sy1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := sx0; sx < sx1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					count++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(b / count)
+			dst.Pix[i+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+func span(i, dstLen, srcLen int) (int, int) {
+	start := i * srcLen / dstLen
+	end := (i + 1) * srcLen / dstLen
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+// jpegOrientation returns the EXIF orientation tag of a JPEG, or 1 when it
+// has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			o, err := exifOrientation(segment[6:])
+			if err != nil {
+				return 1
+			}
+			return o
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) (int, error) {
+	if len(tiff) < 8 {
+		return 0, errors.New("short tiff header")
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errors.New("invalid byte order")
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0, errors.New("ifd out of range")
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := range entries {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:])), nil
+		}
+	}
+	return 1, nil
+}
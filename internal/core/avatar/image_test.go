@@ -0,0 +1,149 @@
+package avatar_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/avatar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halves returns a w by h image whose left half is red and right half is
+// blue.
+func halves(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// encodeJPEG encodes img and, when orientation is set, inserts an EXIF
+// segment carrying it right after the start of image marker.
+func encodeJPEG(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	tiff = append(tiff, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+func decode(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, format, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", format)
+	return img
+}
+
+func isRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xC000 && b < 0x4000
+}
+
+func isBlue(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return b > 0xC000 && r < 0x4000
+}
+
+func TestProcessProducesEverySize(t *testing.T) {
+	out, err := avatar.Process(encodePNG(t, halves(300, 200)))
+	require.NoError(t, err)
+
+	require.Len(t, out, len(avatar.Sizes))
+	for _, size := range avatar.Sizes {
+		img := decode(t, out[size])
+		assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds())
+	}
+}
+
+func TestProcessCropsToCenteredSquare(t *testing.T) {
+	out, err := avatar.Process(encodePNG(t, halves(40, 20)))
+	require.NoError(t, err)
+
+	img := decode(t, out[64])
+	assert.True(t, isRed(img.At(4, 32)))
+	assert.True(t, isBlue(img.At(60, 32)))
+}
+
+func TestProcessAppliesExifOrientation(t *testing.T) {
+	// Orientation 6 means the stored pixels must be rotated 90° clockwise,
+	// which moves the red left half to the top.
+	out, err := avatar.Process(encodeJPEG(t, halves(40, 20), 6))
+	require.NoError(t, err)
+
+	img := decode(t, out[64])
+	assert.True(t, isRed(img.At(32, 4)))
+	assert.True(t, isBlue(img.At(32, 60)))
+}
+
+func TestProcessStripsMetadata(t *testing.T) {
+	out, err := avatar.Process(encodeJPEG(t, halves(40, 20), 6))
+	require.NoError(t, err)
+
+	for _, data := range out {
+		assert.NotContains(t, string(data), "Exif")
+	}
+}
+
+func TestProcessFlattensTransparency(t *testing.T) {
+	out, err := avatar.Process(encodePNG(t, image.NewNRGBA(image.Rect(0, 0, 10, 10))))
+	require.NoError(t, err)
+
+	r, g, b, _ := decode(t, out[64]).At(32, 32).RGBA()
+	assert.Greater(t, r, uint32(0xF000))
+	assert.Greater(t, g, uint32(0xF000))
+	assert.Greater(t, b, uint32(0xF000))
+}
+
+func TestProcessRejectsUnsupportedData(t *testing.T) {
+	_, err := avatar.Process([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"))
+	assert.ErrorIs(t, err, avatar.ErrUnsupportedImage)
+}
+
+func TestProcessRejectsHugeDimensions(t *testing.T) {
+	data := encodePNG(t, halves(2, 2))
+
+	// Rewrite the IHDR chunk to claim a 100000x100000 image and fix its CRC.
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+
+	_, err := avatar.Process(data)
+	assert.ErrorIs(t, err, avatar.ErrImageTooLarge)
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Organization struct {
+	Id        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Membership struct {
+	Organization Organization
+	UserId       uuid.UUID
+	Role         string
+	CreatedAt    time.Time
+}
+
+const (
+	MembershipRoleOwner  = "owner"
+	MembershipRoleAdmin  = "admin"
+	MembershipRoleMember = "member"
+)
+
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+type Invitation struct {
+	Id           uuid.UUID
+	Organization Organization
+	Email        string
+	Role         string
+	InvitedBy    uuid.UUID
+	ExpiresAt    time.Time
+	AcceptedAt   *time.Time
+	RevokedAt    *time.Time
+	CreatedAt    time.Time
+}
+
+func (i Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
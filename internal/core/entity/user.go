@@ -4,11 +4,76 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+)
+
+const (
+	UserStatusPending   = "pending"
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDeleted   = "deleted"
 )
 
 type User struct {
-	Id        uuid.UUID
-	Email     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id          uuid.UUID
+	Email       string
+	Status      string
+	DisplayName string
+	Locale      string
+	Timezone    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ProfilePatch holds the profile fields to change. Nil fields are left as
+// they are and empty strings clear the field.
+type ProfilePatch struct {
+	DisplayName *string
+	Locale      *string
+	Timezone    *string
+}
+
+// Avatar describes the pictures available for a user: the version of an
+// uploaded avatar, if any, and the picture of their identity provider.
+type Avatar struct {
+	Version    string
+	PictureURL string
+}
+
+// PurgedUser is what is left to clean up outside of the database once an
+// account is hard deleted.
+type PurgedUser struct {
+	Id            uuid.UUID
+	AvatarVersion string
+}
+
+type UserFilter struct {
+	Email         string
+	Provider      string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Status        string
+	Page          page.Request
+}
+
+type LinkedAccount struct {
+	Provider       string
+	ProviderUserId string
+	UpdatedAt      time.Time
+}
+
+type Session struct {
+	Id         uuid.UUID
+	StartedAt  time.Time
+	ExpiresAt  time.Time
+	AuthTime   time.Time
+	AuthMethod string
+	EvictedAt  *time.Time
+	DPoPBound  bool
+}
+
+type UserDetails struct {
+	User           User
+	LinkedAccounts []LinkedAccount
+	Sessions       []Session
 }
@@ -1,7 +1,10 @@
 package core
 
 var (
-	ErrNotFound = Error{"not found"}
+	ErrNotFound  = Error{"not found"}
+	ErrForbidden = Error{"forbidden"}
+	ErrInvalid   = Error{"invalid input"}
+	ErrConflict  = Error{"conflict"}
 )
 
 type Error struct {
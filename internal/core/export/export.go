@@ -0,0 +1,307 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+)
+
+const (
+	manifestName = "manifest.json"
+)
+
+var (
+	ErrExportInProgress = fmt.Errorf("%w: an export is already in progress", core.ErrConflict)
+	ErrExportNotReady   = fmt.Errorf("%w: export is not ready", core.ErrConflict)
+)
+
+// Source returns every record it holds about a user, ready to be encoded as
+// JSON.
+type Source func(ctx context.Context, userId uuid.UUID) (any, error)
+
+type Store interface {
+	// Insert returns core.ErrConflict when the user already has an export
+	// pending or running.
+	Insert(ctx context.Context, userId uuid.UUID) (entity.DataExport, error)
+	Get(ctx context.Context, userId, id uuid.UUID) (entity.DataExport, error)
+	ClaimPending(ctx context.Context) (entity.DataExport, error)
+	Complete(ctx context.Context, id uuid.UUID, blobKey string, expiresAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+	// GetExpired returns exports past their retention, including those of
+	// purged users, with only Id and BlobKey set.
+	GetExpired(ctx context.Context, now time.Time) ([]entity.DataExport, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Quota interface {
+	Consume(ctx context.Context, subject plan.Subject, key string, amount int64) error
+	Refund(ctx context.Context, subject plan.Subject, key string, amount int64) error
+}
+
+type Policy struct {
+	Retention    time.Duration
+	PollInterval time.Duration
+}
+
+type Service struct {
+	store   Store
+	blobs   BlobStore
+	signer  *Signer
+	policy  Policy
+	quota   Quota
+	sources map[string]Source
+	wake    chan struct{}
+}
+
+func New(store Store, blobs BlobStore, signer *Signer, policy Policy, quota Quota) *Service {
+	return &Service{
+		store:   store,
+		blobs:   blobs,
+		signer:  signer,
+		policy:  policy,
+		quota:   quota,
+		sources: make(map[string]Source),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Register adds a source whose records end up in <name>.json inside the
+// archive. It must be called before Run.
+func (s *Service) Register(name string, source Source) {
+	s.sources[name] = source
+}
+
+// Request queues an export of the user's data. Exports are metered as api
+// requests of the caller's plan, and refunded when none gets queued.
+func (s *Service) Request(ctx context.Context, userId uuid.UUID) (entity.DataExport, error) {
+	subject := plan.SubjectFrom(ctx)
+	if err := s.quota.Consume(ctx, subject, plan.KeyAPIRequests, 1); err != nil {
+		return entity.DataExport{}, err
+	}
+
+	e, err := s.store.Insert(ctx, userId)
+	if err != nil {
+		if err := s.quota.Refund(ctx, subject, plan.KeyAPIRequests, 1); err != nil {
+			slog.Error(
+				"refunding export quota",
+				slog.Any("error", err),
+				slog.String("user_id", userId.String()),
+			)
+		}
+	}
+	if errors.Is(err, core.ErrConflict) {
+		return entity.DataExport{}, ErrExportInProgress
+	} else if err != nil {
+		return entity.DataExport{}, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return e, nil
+}
+
+func (s *Service) Get(ctx context.Context, userId, id uuid.UUID) (entity.DataExport, error) {
+	return s.store.Get(ctx, userId, id)
+}
+
+// DownloadURL signs a link to the archive of a completed export. The link
+// stops working after the signer's TTL even if the archive is still kept.
+func (s *Service) DownloadURL(e entity.DataExport) (string, time.Time, error) {
+	if e.Status != entity.ExportStatusCompleted {
+		return "", time.Time{}, ErrExportNotReady
+	}
+	url, expiresAt := s.signer.Sign(e.Id, time.Now())
+	return url, expiresAt, nil
+}
+
+// Open returns the archive of the export when the signature and expiry taken
+// from a download link are valid.
+func (s *Service) Open(ctx context.Context, id uuid.UUID, expires int64, signature string) (io.ReadSeekCloser, error) {
+	if err := s.signer.Verify(id, expires, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.blobs.Open(ctx, blobKey(id))
+}
+
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processPending(ctx)
+		s.deleteExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *Service) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		e, err := s.store.ClaimPending(ctx)
+		if errors.Is(err, core.ErrNotFound) {
+			return
+		} else if err != nil {
+			slog.Error(
+				"claiming data export",
+				slog.Any("error", err),
+			)
+			return
+		}
+
+		if err := s.process(ctx, e); err != nil {
+			slog.Error(
+				"processing data export",
+				slog.Any("error", err),
+				slog.String("export_id", e.Id.String()),
+			)
+			if err := s.store.Fail(ctx, e.Id, "export could not be generated"); err != nil {
+				slog.Error(
+					"marking data export as failed",
+					slog.Any("error", err),
+					slog.String("export_id", e.Id.String()),
+				)
+			}
+		}
+	}
+}
+
+func (s *Service) process(ctx context.Context, e entity.DataExport) error {
+	archive, err := s.build(ctx, e.UserId, time.Now())
+	if err != nil {
+		return err
+	}
+
+	key := blobKey(e.Id)
+	if err := s.blobs.Put(ctx, key, archive); err != nil {
+		return fmt.Errorf("storing archive: %w", err)
+	}
+
+	err = s.store.Complete(ctx, e.Id, key, time.Now().Add(s.policy.Retention))
+	if errors.Is(err, core.ErrNotFound) {
+		// The export was reaped while running because its user was purged.
+		return s.blobs.Delete(ctx, key)
+	}
+	return err
+}
+
+type manifest struct {
+	UserId      uuid.UUID `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+func (s *Service) build(ctx context.Context, userId uuid.UUID, now time.Time) (io.Reader, error) {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	m := manifest{UserId: userId, GeneratedAt: now.UTC()}
+	for _, name := range names {
+		records, err := s.sources[name](ctx, userId)
+		if err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", name, err)
+		}
+
+		file := name + ".json"
+		if err := writeJSON(zw, file, now, records); err != nil {
+			return nil, err
+		}
+		m.Files = append(m.Files, file)
+	}
+
+	if err := writeJSON(zw, manifestName, now, m); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("closing archive: %w", err)
+	}
+
+	return &buf, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, modified time.Time, v any) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return fmt.Errorf("adding %s to archive: %w", name, err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding %s: %w", name, err)
+	}
+	return nil
+}
+
+// deleteExpired removes the archive before its row, so an archive that could
+// not be deleted is retried on the next run instead of being orphaned.
+func (s *Service) deleteExpired(ctx context.Context) {
+	exports, err := s.store.GetExpired(ctx, time.Now())
+	if err != nil {
+		slog.Error(
+			"getting expired data exports",
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	for _, e := range exports {
+		// Failed exports never stored an archive.
+		if e.BlobKey != "" {
+			if err := s.blobs.Delete(ctx, e.BlobKey); err != nil {
+				slog.Error(
+					"deleting data export archive",
+					slog.Any("error", err),
+					slog.String("export_id", e.Id.String()),
+				)
+				continue
+			}
+		}
+
+		if err := s.store.Delete(ctx, e.Id); err != nil && !errors.Is(err, core.ErrNotFound) {
+			slog.Error(
+				"deleting data export",
+				slog.Any("error", err),
+				slog.String("export_id", e.Id.String()),
+			)
+		}
+	}
+}
+
+func blobKey(id uuid.UUID) string {
+	return "exports/" + id.String() + ".zip"
+}
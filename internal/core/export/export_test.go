@@ -0,0 +1,261 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/export"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	export.Store
+
+	pending   []entity.DataExport
+	completed map[uuid.UUID]string
+	expired   []entity.DataExport
+	deleted   []uuid.UUID
+	onDone    func()
+	insertErr error
+}
+
+type fakeQuota struct {
+	used       int64
+	consumeErr error
+}
+
+func (q *fakeQuota) Consume(ctx context.Context, subject plan.Subject, key string, amount int64) error {
+	if q.consumeErr != nil {
+		return q.consumeErr
+	}
+	q.used += amount
+	return nil
+}
+
+func (q *fakeQuota) Refund(ctx context.Context, subject plan.Subject, key string, amount int64) error {
+	q.used -= amount
+	return nil
+}
+
+func (s *fakeStore) Insert(ctx context.Context, userId uuid.UUID) (entity.DataExport, error) {
+	if s.insertErr != nil {
+		return entity.DataExport{}, s.insertErr
+	}
+	return entity.DataExport{Id: uuid.New(), UserId: userId, Status: entity.ExportStatusPending}, nil
+}
+
+func (s *fakeStore) ClaimPending(ctx context.Context) (entity.DataExport, error) {
+	if len(s.pending) == 0 {
+		return entity.DataExport{}, core.ErrNotFound
+	}
+	e := s.pending[0]
+	s.pending = s.pending[1:]
+	return e, nil
+}
+
+func (s *fakeStore) Complete(ctx context.Context, id uuid.UUID, blobKey string, expiresAt time.Time) error {
+	s.completed[id] = blobKey
+	s.onDone()
+	return nil
+}
+
+func (s *fakeStore) GetExpired(ctx context.Context, now time.Time) ([]entity.DataExport, error) {
+	return s.expired, nil
+}
+
+func (s *fakeStore) Delete(ctx context.Context, id uuid.UUID) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+type fakeBlobs struct {
+	export.BlobStore
+
+	blobs      map[string][]byte
+	failDelete bool
+}
+
+func (b *fakeBlobs) Delete(ctx context.Context, key string) error {
+	if b.failDelete {
+		return errors.New("blob store unavailable")
+	}
+	delete(b.blobs, key)
+	return nil
+}
+
+func (b *fakeBlobs) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	b.blobs[key] = data
+	return err
+}
+
+func TestRunBuildsArchiveFromSources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	userId := uuid.New()
+	exportId := uuid.New()
+	store := &fakeStore{
+		pending:   []entity.DataExport{{Id: exportId, UserId: userId}},
+		completed: make(map[uuid.UUID]string),
+		onDone:    cancel,
+	}
+	blobs := &fakeBlobs{blobs: make(map[string][]byte)}
+
+	s := export.New(store, blobs, export.NewSigner([]byte("secret"), time.Minute, ""), export.Policy{
+		Retention:    time.Hour,
+		PollInterval: time.Hour,
+	}, &fakeQuota{})
+	s.Register("profile", func(ctx context.Context, id uuid.UUID) (any, error) {
+		return map[string]string{"id": id.String()}, nil
+	})
+
+	s.Run(ctx)
+
+	key, ok := store.completed[exportId]
+	require.True(t, ok)
+
+	archive := blobs.blobs[key]
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+	assert.Len(t, files, 2)
+
+	var profile map[string]string
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, userId.String(), profile["id"])
+
+	var manifest struct {
+		UserId uuid.UUID `json:"user_id"`
+		Files  []string  `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, userId, manifest.UserId)
+	assert.Equal(t, []string{"profile.json"}, manifest.Files)
+}
+
+func TestRunDeletesExpiredExports(t *testing.T) {
+	completed := entity.DataExport{Id: uuid.New(), BlobKey: "exports/completed.zip"}
+	failed := entity.DataExport{Id: uuid.New()}
+
+	tests := []struct {
+		name        string
+		failDelete  bool
+		wantDeleted []uuid.UUID
+	}{
+		{"should delete archives then rows", false, []uuid.UUID{completed.Id, failed.Id}},
+		{"should keep rows whose archive could not be deleted", true, []uuid.UUID{failed.Id}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			store := &fakeStore{expired: []entity.DataExport{completed, failed}}
+			blobs := &fakeBlobs{
+				blobs:      map[string][]byte{completed.BlobKey: []byte("archive")},
+				failDelete: tt.failDelete,
+			}
+
+			s := export.New(store, blobs, export.NewSigner([]byte("secret"), time.Minute, ""), export.Policy{
+				Retention:    time.Hour,
+				PollInterval: time.Hour,
+			}, &fakeQuota{})
+			s.Run(ctx)
+
+			assert.Equal(t, tt.wantDeleted, store.deleted)
+			if !tt.failDelete {
+				assert.NotContains(t, blobs.blobs, completed.BlobKey)
+			}
+		})
+	}
+}
+
+func TestRequestQuota(t *testing.T) {
+	tests := []struct {
+		name       string
+		consumeErr error
+		insertErr  error
+		err        error
+		used       int64
+	}{
+		{"should consume quota for queued exports", nil, nil, nil, 1},
+		{"should refund quota when an export is in progress", nil, core.ErrConflict, export.ErrExportInProgress, 0},
+		{"should not queue exports past the quota", plan.QuotaExceededError{Key: plan.KeyAPIRequests}, nil, plan.QuotaExceededError{Key: plan.KeyAPIRequests}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota := &fakeQuota{consumeErr: tt.consumeErr}
+			s := export.New(&fakeStore{insertErr: tt.insertErr}, &fakeBlobs{}, export.NewSigner([]byte("secret"), time.Minute, ""), export.Policy{}, quota)
+
+			_, err := s.Request(context.Background(), uuid.New())
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.used, quota.used)
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer := export.NewSigner([]byte("secret"), time.Minute, "https://example.com/exports")
+	id := uuid.New()
+	now := time.Now()
+
+	link, expiresAt := signer.Sign(id, now)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/exports/"+id.String()+"/download", u.Path)
+
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := u.Query().Get("signature")
+
+	tests := []struct {
+		name      string
+		id        uuid.UUID
+		expires   int64
+		signature string
+		now       time.Time
+		wantErr   bool
+	}{
+		{"should accept a fresh link", id, expires, signature, now, false},
+		{"should reject an expired link", id, expires, signature, expiresAt, true},
+		{"should reject another export", uuid.New(), expires, signature, now, true},
+		{"should reject an extended expiry", id, expires + 3600, signature, now, true},
+		{"should reject a malformed signature", id, expires, "%%%", now, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := signer.Verify(tt.id, tt.expires, tt.signature, tt.now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, core.ErrForbidden)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+var (
+	ErrInvalidSignature = fmt.Errorf("%w: invalid or expired download link", core.ErrForbidden)
+)
+
+// Signer issues download links whose expiry is covered by an HMAC, so they
+// can be handed out without storing them.
+type Signer struct {
+	key     []byte
+	ttl     time.Duration
+	baseURL string
+}
+
+func NewSigner(key []byte, ttl time.Duration, baseURL string) *Signer {
+	return &Signer{
+		key:     key,
+		ttl:     ttl,
+		baseURL: baseURL,
+	}
+}
+
+func (s *Signer) Sign(id uuid.UUID, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+	expires := expiresAt.Unix()
+
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("signature", s.signature(id, expires))
+
+	return fmt.Sprintf("%s/%s/download?%s", s.baseURL, id, q.Encode()), expiresAt
+}
+
+func (s *Signer) Verify(id uuid.UUID, expires int64, signature string, now time.Time) error {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	want, _ := base64.RawURLEncoding.DecodeString(s.signature(id, expires))
+	if !hmac.Equal(got, want) || !now.Before(time.Unix(expires, 0)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *Signer) signature(id uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "data-export:%s:%d", id, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
+)
+
+type UserReader interface {
+	Get(ctx context.Context, id uuid.UUID) (entity.User, error)
+}
+
+type LinkedAccountReader interface {
+	GetLinkedAccounts(ctx context.Context, userId uuid.UUID) ([]entity.LinkedAccount, error)
+}
+
+type SessionReader interface {
+	GetSessions(ctx context.Context, userId uuid.UUID) ([]entity.Session, error)
+}
+
+type MembershipReader interface {
+	ListByUser(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error)
+}
+
+type SettingsReader interface {
+	Get(ctx context.Context, userId uuid.UUID) (settings.Settings, error)
+}
+
+type userRecord struct {
+	Id          uuid.UUID `json:"id"`
+	Email       string    `json:"email"`
+	Status      string    `json:"status"`
+	DisplayName string    `json:"display_name"`
+	Locale      string    `json:"locale"`
+	Timezone    string    `json:"timezone"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type linkedAccountRecord struct {
+	Provider       string    `json:"provider"`
+	ProviderUserId string    `json:"provider_user_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type sessionRecord struct {
+	Id         uuid.UUID  `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AuthTime   time.Time  `json:"auth_time"`
+	AuthMethod string     `json:"auth_method"`
+	EvictedAt  *time.Time `json:"evicted_at"`
+}
+
+type settingsRecord struct {
+	DefaultsVersion int            `json:"defaults_version"`
+	Values          map[string]any `json:"values"`
+}
+
+type membershipRecord struct {
+	OrganizationId   uuid.UUID `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	Role             string    `json:"role"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func UserSource(users UserReader) Source {
+	return func(ctx context.Context, userId uuid.UUID) (any, error) {
+		u, err := users.Get(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		return userRecord{
+			Id:          u.Id,
+			Email:       u.Email,
+			Status:      u.Status,
+			DisplayName: u.DisplayName,
+			Locale:      u.Locale,
+			Timezone:    u.Timezone,
+			CreatedAt:   u.CreatedAt,
+			UpdatedAt:   u.UpdatedAt,
+		}, nil
+	}
+}
+
+func LinkedAccountSource(accounts LinkedAccountReader) Source {
+	return func(ctx context.Context, userId uuid.UUID) (any, error) {
+		all, err := accounts.GetLinkedAccounts(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		records := make([]linkedAccountRecord, 0, len(all))
+		for _, la := range all {
+			records = append(records, linkedAccountRecord{
+				Provider:       la.Provider,
+				ProviderUserId: la.ProviderUserId,
+				UpdatedAt:      la.UpdatedAt,
+			})
+		}
+		return records, nil
+	}
+}
+
+func SessionSource(sessions SessionReader) Source {
+	return func(ctx context.Context, userId uuid.UUID) (any, error) {
+		all, err := sessions.GetSessions(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		records := make([]sessionRecord, 0, len(all))
+		for _, s := range all {
+			records = append(records, sessionRecord{
+				Id:         s.Id,
+				StartedAt:  s.StartedAt,
+				ExpiresAt:  s.ExpiresAt,
+				AuthTime:   s.AuthTime,
+				AuthMethod: s.AuthMethod,
+				EvictedAt:  s.EvictedAt,
+			})
+		}
+		return records, nil
+	}
+}
+
+func MembershipSource(memberships MembershipReader) Source {
+	return func(ctx context.Context, userId uuid.UUID) (any, error) {
+		all, err := memberships.ListByUser(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		records := make([]membershipRecord, 0, len(all))
+		for _, m := range all {
+			records = append(records, membershipRecord{
+				OrganizationId:   m.Organization.Id,
+				OrganizationName: m.Organization.Name,
+				Role:             m.Role,
+				CreatedAt:        m.CreatedAt,
+			})
+		}
+		return records, nil
+	}
+}
+
+func SettingsSource(s SettingsReader) Source {
+	return func(ctx context.Context, userId uuid.UUID) (any, error) {
+		current, err := s.Get(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		return settingsRecord{
+			DefaultsVersion: current.DefaultsVersion,
+			Values:          current.Values,
+		}, nil
+	}
+}
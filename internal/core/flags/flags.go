@@ -0,0 +1,233 @@
+package flags
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+var (
+	keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,99}$`)
+)
+
+type Rules struct {
+	Percentage    int
+	Environments  []string
+	Users         []uuid.UUID
+	Organizations []uuid.UUID
+}
+
+type Flag struct {
+	Key         string
+	Description string
+	Enabled     bool
+	Rules       Rules
+	UpdatedAt   time.Time
+}
+
+type Subject struct {
+	UserId uuid.UUID
+	OrgId  uuid.UUID
+}
+
+func SubjectFrom(ctx context.Context) Subject {
+	p, _ := policy.PrincipalFrom(ctx)
+	return Subject{
+		UserId: p.UserId,
+		OrgId:  p.OrgId,
+	}
+}
+
+// Evaluate turns a flag on when it is enabled, the environment matches and
+// the subject is either allowlisted or falls inside the rollout percentage.
+// Rollout buckets are stable per flag and user, so raising the percentage
+// only ever adds users.
+func (f Flag) Evaluate(env string, s Subject) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if len(f.Rules.Environments) > 0 && !slices.Contains(f.Rules.Environments, env) {
+		return false
+	}
+
+	if s.UserId != uuid.Nil && slices.Contains(f.Rules.Users, s.UserId) {
+		return true
+	}
+	if s.OrgId != uuid.Nil && slices.Contains(f.Rules.Organizations, s.OrgId) {
+		return true
+	}
+
+	if f.Rules.Percentage >= 100 {
+		return true
+	}
+	if f.Rules.Percentage <= 0 || s.UserId == uuid.Nil {
+		return false
+	}
+	return bucket(f.Key, s.UserId) < f.Rules.Percentage
+}
+
+func (f Flag) Validate() error {
+	if !keyPattern.MatchString(f.Key) {
+		return fmt.Errorf("%w: flag key must match %s", core.ErrInvalid, keyPattern)
+	}
+	if f.Rules.Percentage < 0 || f.Rules.Percentage > 100 {
+		return fmt.Errorf("%w: percentage must be between 0 and 100", core.ErrInvalid)
+	}
+	return nil
+}
+
+// NonNil returns r with nil lists replaced by empty ones, for stores and
+// encoders that tell the two apart.
+func (r Rules) NonNil() Rules {
+	r.Environments = nonNil(r.Environments)
+	r.Users = nonNil(r.Users)
+	r.Organizations = nonNil(r.Organizations)
+	return r
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func bucket(key string, userId uuid.UUID) int {
+	sum := sha256.Sum256(append([]byte(key+":"), userId[:]...))
+	return int(binary.BigEndian.Uint64(sum[:8]) % 100)
+}
+
+type Store interface {
+	GetAll(ctx context.Context) ([]Flag, error)
+	Upsert(ctx context.Context, f Flag) (Flag, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Service struct {
+	store Store
+	env   string
+	ttl   time.Duration
+
+	mu        sync.RWMutex
+	flags     map[string]Flag
+	expiresAt time.Time
+}
+
+func New(store Store, env string, ttl time.Duration) *Service {
+	return &Service{
+		store: store,
+		env:   env,
+		ttl:   ttl,
+	}
+}
+
+func (s *Service) IsEnabled(ctx context.Context, key string) bool {
+	return s.IsEnabledFor(ctx, key, SubjectFrom(ctx))
+}
+
+func (s *Service) IsEnabledFor(ctx context.Context, key string, subject Subject) bool {
+	flags, err := s.load(ctx)
+	if err != nil {
+		slog.Error(
+			"loading feature flags",
+			slog.Any("error", err),
+			slog.String("flag", key),
+		)
+		return false
+	}
+
+	f, ok := flags[key]
+	return ok && f.Evaluate(s.env, subject)
+}
+
+func (s *Service) EvaluateAll(ctx context.Context, subject Subject) (map[string]bool, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated := make(map[string]bool, len(flags))
+	for key, f := range flags {
+		evaluated[key] = f.Evaluate(s.env, subject)
+	}
+	return evaluated, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]Flag, error) {
+	return s.store.GetAll(ctx)
+}
+
+func (s *Service) Save(ctx context.Context, f Flag) (Flag, error) {
+	if err := f.Validate(); err != nil {
+		return Flag{}, err
+	}
+
+	f, err := s.store.Upsert(ctx, f)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	s.invalidate()
+	return f, nil
+}
+
+func (s *Service) Delete(ctx context.Context, key string) error {
+	if err := s.store.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+func (s *Service) load(ctx context.Context) (map[string]Flag, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	flags, expiresAt := s.flags, s.expiresAt
+	s.mu.RUnlock()
+	if flags != nil && now.Before(expiresAt) {
+		return flags, nil
+	}
+
+	all, err := s.store.GetAll(ctx)
+	if err != nil {
+		if flags != nil {
+			slog.Error(
+				"refreshing feature flags, serving cached ones",
+				slog.Any("error", err),
+			)
+			return flags, nil
+		}
+		return nil, err
+	}
+
+	flags = make(map[string]Flag, len(all))
+	for _, f := range all {
+		flags[f.Key] = f
+	}
+
+	s.mu.Lock()
+	s.flags = flags
+	s.expiresAt = now.Add(s.ttl)
+	s.mu.Unlock()
+
+	return flags, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expiresAt = time.Time{}
+}
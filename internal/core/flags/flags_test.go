@@ -0,0 +1,159 @@
+package flags_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/flags"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	calls int
+	flags []flags.Flag
+}
+
+func (s *fakeStore) GetAll(ctx context.Context) ([]flags.Flag, error) {
+	s.calls++
+	return s.flags, nil
+}
+
+func (s *fakeStore) Upsert(ctx context.Context, f flags.Flag) (flags.Flag, error) {
+	s.flags = append(s.flags, f)
+	return f, nil
+}
+
+func (s *fakeStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestEvaluate(t *testing.T) {
+	user := uuid.New()
+	org := uuid.New()
+
+	tests := []struct {
+		name     string
+		flag     flags.Flag
+		subject  flags.Subject
+		expected bool
+	}{
+		{
+			"should be off when disabled",
+			flags.Flag{Rules: flags.Rules{Percentage: 100}},
+			flags.Subject{UserId: user},
+			false,
+		},
+		{
+			"should be on for everyone at full rollout",
+			flags.Flag{Enabled: true, Rules: flags.Rules{Percentage: 100}},
+			flags.Subject{UserId: user},
+			true,
+		},
+		{
+			"should be off without matching rules",
+			flags.Flag{Enabled: true},
+			flags.Subject{UserId: user},
+			false,
+		},
+		{
+			"should be on for allowlisted user",
+			flags.Flag{Enabled: true, Rules: flags.Rules{Users: []uuid.UUID{user}}},
+			flags.Subject{UserId: user},
+			true,
+		},
+		{
+			"should be on for allowlisted organization",
+			flags.Flag{Enabled: true, Rules: flags.Rules{Organizations: []uuid.UUID{org}}},
+			flags.Subject{UserId: user, OrgId: org},
+			true,
+		},
+		{
+			"should be off in other environments",
+			flags.Flag{Enabled: true, Rules: flags.Rules{Percentage: 100, Environments: []string{"prod"}}},
+			flags.Subject{UserId: user},
+			false,
+		},
+		{
+			"should be off for anonymous subject on partial rollout",
+			flags.Flag{Enabled: true, Rules: flags.Rules{Percentage: 99}},
+			flags.Subject{},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.flag.Evaluate("dev", tt.subject))
+		})
+	}
+}
+
+func TestEvaluatePercentageIsStableAndMonotonic(t *testing.T) {
+	users := make([]uuid.UUID, 1000)
+	for i := range users {
+		users[i] = uuid.New()
+	}
+
+	enabledAt := func(percentage int) map[uuid.UUID]bool {
+		f := flags.Flag{Key: "new-dashboard", Enabled: true, Rules: flags.Rules{Percentage: percentage}}
+		enabled := make(map[uuid.UUID]bool)
+		for _, u := range users {
+			if f.Evaluate("dev", flags.Subject{UserId: u}) {
+				enabled[u] = true
+			}
+		}
+		return enabled
+	}
+
+	ten := enabledAt(10)
+	fifty := enabledAt(50)
+
+	assert.InDelta(t, 100, len(ten), 50)
+	assert.InDelta(t, 500, len(fifty), 80)
+	for u := range ten {
+		assert.True(t, fifty[u])
+	}
+	assert.Equal(t, ten, enabledAt(10))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, flags.Flag{Key: "new-dashboard", Rules: flags.Rules{Percentage: 50}}.Validate())
+	assert.ErrorIs(t, flags.Flag{Key: "New Dashboard"}.Validate(), core.ErrInvalid)
+	assert.ErrorIs(t, flags.Flag{Key: "new-dashboard", Rules: flags.Rules{Percentage: 101}}.Validate(), core.ErrInvalid)
+}
+
+func TestRulesNonNil(t *testing.T) {
+	users := []uuid.UUID{uuid.New()}
+
+	rules := flags.Rules{Percentage: 10, Users: users}.NonNil()
+
+	assert.Equal(t, 10, rules.Percentage)
+	assert.Equal(t, []string{}, rules.Environments)
+	assert.Equal(t, users, rules.Users)
+	assert.Equal(t, []uuid.UUID{}, rules.Organizations)
+}
+
+func TestServiceCachesUntilSave(t *testing.T) {
+	store := &fakeStore{}
+	s := flags.New(store, "dev", time.Minute)
+	user := uuid.New()
+	ctx := policy.WithPrincipal(context.Background(), policy.Principal{UserId: user})
+
+	assert.False(t, s.IsEnabled(ctx, "beta"))
+	assert.False(t, s.IsEnabled(ctx, "beta"))
+	assert.Equal(t, 1, store.calls)
+
+	_, err := s.Save(ctx, flags.Flag{Key: "beta", Enabled: true, Rules: flags.Rules{Users: []uuid.UUID{user}}})
+	require.NoError(t, err)
+
+	assert.True(t, s.IsEnabled(ctx, "beta"))
+	assert.Equal(t, 2, store.calls)
+
+	evaluated, err := s.EvaluateAll(ctx, flags.Subject{UserId: uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"beta": false}, evaluated)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	appmail "github.com/joaovictorsl/go-backend-template/internal/mail"
+)
+
+var ErrInvitationEmailMismatch = fmt.Errorf("%w: invitation was sent to another email", core.ErrForbidden)
+
+type InvitationStore interface {
+	InsertInvitation(ctx context.Context, inv entity.Invitation, tokenHash string) (entity.Invitation, error)
+	ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error)
+	GetPendingInvitation(ctx context.Context, tokenHash string, now time.Time) (entity.Invitation, error)
+	RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error
+	// AcceptInvitation only accepts a pending invitation of orgId addressed
+	// to email.
+	AcceptInvitation(ctx context.Context, orgId uuid.UUID, tokenHash string, userId uuid.UUID, email string, now time.Time) (entity.Membership, error)
+}
+
+type UserReader interface {
+	Get(ctx context.Context, id uuid.UUID) (entity.User, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg appmail.Message) error
+}
+
+type InvitationPolicy struct {
+	TTL       time.Duration
+	AcceptURL string
+}
+
+func (s Service) Invite(ctx context.Context, orgId, inviterId uuid.UUID, email, role string) (entity.Invitation, error) {
+	email, err := appmail.NormalizeAddress(email)
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	if role != entity.MembershipRoleAdmin && role != entity.MembershipRoleMember {
+		return entity.Invitation{}, fmt.Errorf("%w: role must be %s or %s", core.ErrInvalid, entity.MembershipRoleAdmin, entity.MembershipRoleMember)
+	}
+
+	inviter, err := s.orgStore.GetMembership(ctx, orgId, inviterId)
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	inv, err := s.invitationStore.InsertInvitation(ctx, entity.Invitation{
+		Organization: inviter.Organization,
+		Email:        email,
+		Role:         role,
+		InvitedBy:    inviterId,
+		ExpiresAt:    time.Now().Add(s.invitations.TTL),
+	}, tokenHash)
+	if err != nil {
+		return entity.Invitation{}, err
+	}
+
+	err = s.mailer.Send(ctx, appmail.Message{
+		To:      inv.Email,
+		Subject: fmt.Sprintf("You have been invited to join %s", inv.Organization.Name),
+		Body: fmt.Sprintf(
+			"You have been invited to join %s as %s.\n\nAccept the invitation: %s\n\nThis link expires on %s.",
+			inv.Organization.Name,
+			inv.Role,
+			s.acceptURL(token),
+			inv.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		return entity.Invitation{}, fmt.Errorf("sending invitation email: %w", err)
+	}
+
+	return inv, nil
+}
+
+func (s Service) ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error) {
+	return s.invitationStore.ListInvitations(ctx, orgId)
+}
+
+func (s Service) RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error {
+	return s.invitationStore.RevokeInvitation(ctx, orgId, invitationId)
+}
+
+func (s Service) GetPendingInvitation(ctx context.Context, token string) (entity.Invitation, error) {
+	return s.invitationStore.GetPendingInvitation(ctx, HashInvitationToken(token), time.Now())
+}
+
+// AcceptInvitation adds the user to the organization when the invitation
+// was sent to their email, so a forwarded or leaked link is useless to
+// anyone else.
+func (s Service) AcceptInvitation(ctx context.Context, userId uuid.UUID, token string) (entity.Membership, error) {
+	tokenHash := HashInvitationToken(token)
+	now := time.Now()
+
+	inv, err := s.invitationStore.GetPendingInvitation(ctx, tokenHash, now)
+	if err != nil {
+		return entity.Membership{}, err
+	}
+
+	u, err := s.users.Get(ctx, userId)
+	if err != nil {
+		return entity.Membership{}, err
+	}
+
+	email, err := appmail.NormalizeAddress(u.Email)
+	if err != nil || email != inv.Email {
+		return entity.Membership{}, ErrInvitationEmailMismatch
+	}
+
+	return s.invitationStore.AcceptInvitation(ctx, inv.Organization.Id, tokenHash, userId, email, now)
+}
+
+func (s Service) acceptURL(token string) string {
+	return fmt.Sprintf("%s?token=%s", s.invitations.AcceptURL, url.QueryEscape(token))
+}
+
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newInvitationToken() (token string, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating invitation token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashInvitationToken(token), nil
+}
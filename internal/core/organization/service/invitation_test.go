@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/organization/service"
+	"github.com/joaovictorsl/go-backend-template/internal/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	service.OrganizationStore
+	service.InvitationStore
+
+	org       entity.Organization
+	tokenHash string
+	pending   entity.Invitation
+	accepted  bool
+}
+
+func (s *fakeStore) GetPendingInvitation(ctx context.Context, tokenHash string, now time.Time) (entity.Invitation, error) {
+	if tokenHash != s.tokenHash {
+		return entity.Invitation{}, core.ErrNotFound
+	}
+	return s.pending, nil
+}
+
+func (s *fakeStore) AcceptInvitation(
+	ctx context.Context,
+	orgId uuid.UUID,
+	tokenHash string,
+	userId uuid.UUID,
+	email string,
+	now time.Time,
+) (entity.Membership, error) {
+	if orgId != s.pending.Organization.Id || tokenHash != s.tokenHash || email != s.pending.Email {
+		return entity.Membership{}, core.ErrNotFound
+	}
+	s.accepted = true
+	return entity.Membership{Organization: s.org, UserId: userId, Role: s.pending.Role}, nil
+}
+
+type fakeUsers struct {
+	user entity.User
+}
+
+func (u fakeUsers) Get(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	if id != u.user.Id {
+		return entity.User{}, core.ErrNotFound
+	}
+	return u.user, nil
+}
+
+func (s *fakeStore) GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error) {
+	if orgId != s.org.Id {
+		return entity.Membership{}, core.ErrNotFound
+	}
+	return entity.Membership{Organization: s.org, UserId: userId, Role: entity.MembershipRoleOwner}, nil
+}
+
+func (s *fakeStore) InsertInvitation(ctx context.Context, inv entity.Invitation, tokenHash string) (entity.Invitation, error) {
+	s.tokenHash = tokenHash
+	inv.Id = uuid.New()
+	return inv, nil
+}
+
+type fakeMailer struct {
+	sent []mail.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestInvite(t *testing.T) {
+	org := entity.Organization{Id: uuid.New(), Name: "Acme"}
+
+	tests := []struct {
+		name    string
+		email   string
+		role    string
+		wantErr error
+	}{
+		{"should invite member", "Jane@Example.com ", entity.MembershipRoleMember, nil},
+		{"should invite admin", "jane@example.com", entity.MembershipRoleAdmin, nil},
+		{"should reject owner role", "jane@example.com", entity.MembershipRoleOwner, core.ErrInvalid},
+		{"should reject invalid email", "jane", entity.MembershipRoleMember, core.ErrInvalid},
+		{"should reject email with display name", "Jane <jane@example.com>", entity.MembershipRoleMember, core.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{org: org}
+			mailer := &fakeMailer{}
+			s := service.New(store, store, fakeUsers{}, mailer, service.InvitationPolicy{
+				TTL:       time.Hour,
+				AcceptURL: "http://example.com/invitations/accept",
+			})
+
+			inv, err := s.Invite(context.Background(), org.Id, uuid.New(), tt.email, tt.role)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, mailer.sent)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "jane@example.com", inv.Email)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), inv.ExpiresAt, time.Second)
+			require.Len(t, mailer.sent, 1)
+			assert.Equal(t, "jane@example.com", mailer.sent[0].To)
+
+			link := regexp.MustCompile(`http://example\.com/invitations/accept\?token=\S+`).FindString(mailer.sent[0].Body)
+			require.NotEmpty(t, link)
+			u, err := url.Parse(link)
+			require.NoError(t, err)
+
+			token := u.Query().Get("token")
+			assert.NotEqual(t, store.tokenHash, token)
+			assert.Equal(t, store.tokenHash, service.HashInvitationToken(token))
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	org := entity.Organization{Id: uuid.New(), Name: "Acme"}
+	token := "invitation-token"
+
+	tests := []struct {
+		name      string
+		userEmail string
+		token     string
+		wantErr   error
+	}{
+		{"should accept invitation sent to user email", "jane@example.com", token, nil},
+		{"should accept regardless of email case", "Jane@Example.com", token, nil},
+		{"should reject user with another email", "john@example.com", token, core.ErrForbidden},
+		{"should reject unknown token", "jane@example.com", "other-token", core.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{
+				org:       org,
+				tokenHash: service.HashInvitationToken(token),
+				pending: entity.Invitation{
+					Organization: org,
+					Email:        "jane@example.com",
+					Role:         entity.MembershipRoleMember,
+				},
+			}
+			user := entity.User{Id: uuid.New(), Email: tt.userEmail}
+			s := service.New(store, store, fakeUsers{user}, &fakeMailer{}, service.InvitationPolicy{})
+
+			m, err := s.AcceptInvitation(context.Background(), user.Id, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, store.accepted)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.True(t, store.accepted)
+			assert.Equal(t, org.Id, m.Organization.Id)
+			assert.Equal(t, user.Id, m.UserId)
+			assert.Equal(t, entity.MembershipRoleMember, m.Role)
+		})
+	}
+}
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		inv      entity.Invitation
+		expected string
+	}{
+		{"should be pending", entity.Invitation{ExpiresAt: now.Add(time.Hour)}, entity.InvitationStatusPending},
+		{"should be expired", entity.Invitation{ExpiresAt: now}, entity.InvitationStatusExpired},
+		{"should be accepted", entity.Invitation{ExpiresAt: now, AcceptedAt: &now}, entity.InvitationStatusAccepted},
+		{"should be revoked", entity.Invitation{ExpiresAt: now.Add(time.Hour), RevokedAt: &now}, entity.InvitationStatusRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.inv.Status(now))
+		})
+	}
+}
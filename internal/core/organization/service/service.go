@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+)
+
+const (
+	MaxNameLength = 100
+)
+
+type OrganizationStore interface {
+	Insert(ctx context.Context, name string, ownerId uuid.UUID, ownerRole string) (entity.Membership, error)
+	GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error)
+	ListByUser(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error)
+}
+
+type Service struct {
+	orgStore        OrganizationStore
+	invitationStore InvitationStore
+	users           UserReader
+	mailer          Mailer
+	invitations     InvitationPolicy
+}
+
+func New(
+	orgStore OrganizationStore,
+	invitationStore InvitationStore,
+	users UserReader,
+	mailer Mailer,
+	invitations InvitationPolicy,
+) *Service {
+	return &Service{
+		orgStore:        orgStore,
+		invitationStore: invitationStore,
+		users:           users,
+		mailer:          mailer,
+		invitations:     invitations,
+	}
+}
+
+func (s Service) Create(ctx context.Context, ownerId uuid.UUID, name string) (entity.Membership, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return entity.Membership{}, fmt.Errorf("%w: name must have between 1 and %d characters", core.ErrInvalid, MaxNameLength)
+	}
+
+	return s.orgStore.Insert(ctx, name, ownerId, entity.MembershipRoleOwner)
+}
+
+func (s Service) GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error) {
+	return s.orgStore.GetMembership(ctx, orgId, userId)
+}
+
+func (s Service) ListByUser(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error) {
+	return s.orgStore.ListByUser(ctx, userId)
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+const (
+	InvitationKind = "invitation"
+)
+
+func Policies() []policy.Rule {
+	return []policy.Rule{
+		policy.OrgRoleCan(entity.MembershipRoleOwner, InvitationKind, policy.ActionCreate, policy.ActionRead, policy.ActionDelete),
+		policy.OrgRoleCan(entity.MembershipRoleAdmin, InvitationKind, policy.ActionCreate, policy.ActionRead, policy.ActionDelete),
+	}
+}
+
+func invitationResource(orgId uuid.UUID) policy.Resource {
+	return policy.Resource{
+		Kind:  InvitationKind,
+		OrgId: orgId,
+	}
+}
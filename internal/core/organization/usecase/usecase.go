@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+type Service interface {
+	Create(ctx context.Context, ownerId uuid.UUID, name string) (entity.Membership, error)
+	GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error)
+	ListByUser(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error)
+	Invite(ctx context.Context, orgId, inviterId uuid.UUID, email, role string) (entity.Invitation, error)
+	ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error)
+	RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error
+	GetPendingInvitation(ctx context.Context, token string) (entity.Invitation, error)
+	AcceptInvitation(ctx context.Context, userId uuid.UUID, token string) (entity.Membership, error)
+}
+
+type Authorizer interface {
+	Authorize(ctx context.Context, action string, resource policy.Resource) error
+}
+
+type Quota interface {
+	Consume(ctx context.Context, subject plan.Subject, key string, amount int64) error
+	Refund(ctx context.Context, subject plan.Subject, key string, amount int64) error
+}
+
+type UseCase struct {
+	orgService Service
+	authorizer Authorizer
+	quota      Quota
+}
+
+func New(orgService Service, authorizer Authorizer, quota Quota) *UseCase {
+	return &UseCase{
+		orgService: orgService,
+		authorizer: authorizer,
+		quota:      quota,
+	}
+}
+
+func (u *UseCase) Create(ctx context.Context, userId uuid.UUID, name string) (entity.Membership, error) {
+	return u.orgService.Create(ctx, userId, name)
+}
+
+func (u *UseCase) List(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error) {
+	return u.orgService.ListByUser(ctx, userId)
+}
+
+func (u *UseCase) Switch(ctx context.Context, userId uuid.UUID, orgId uuid.UUID) (entity.Membership, error) {
+	return u.orgService.GetMembership(ctx, orgId, userId)
+}
+
+func (u *UseCase) Invite(ctx context.Context, userId, orgId uuid.UUID, email, role string) (entity.Invitation, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionCreate, invitationResource(orgId)); err != nil {
+		return entity.Invitation{}, err
+	}
+
+	subject := plan.Subject{Type: plan.SubjectOrganization, Id: orgId}
+	if err := u.quota.Consume(ctx, subject, plan.KeyInvitations, 1); err != nil {
+		return entity.Invitation{}, err
+	}
+
+	inv, err := u.orgService.Invite(ctx, orgId, userId, email, role)
+	if err != nil {
+		// Only invitations that were actually sent count against the quota.
+		if err := u.quota.Refund(ctx, subject, plan.KeyInvitations, 1); err != nil {
+			slog.Error(
+				"refunding invitation quota",
+				slog.Any("error", err),
+				slog.String("organization_id", orgId.String()),
+			)
+		}
+		return entity.Invitation{}, err
+	}
+	return inv, nil
+}
+
+func (u *UseCase) ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionRead, invitationResource(orgId)); err != nil {
+		return nil, err
+	}
+
+	return u.orgService.ListInvitations(ctx, orgId)
+}
+
+func (u *UseCase) RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error {
+	resource := invitationResource(orgId)
+	resource.Id = invitationId
+	if err := u.authorizer.Authorize(ctx, policy.ActionDelete, resource); err != nil {
+		return err
+	}
+
+	return u.orgService.RevokeInvitation(ctx, orgId, invitationId)
+}
+
+// Invitations are looked up by token before the invitee belongs to the
+// organization, so these run outside of the caller's tenant.
+func (u *UseCase) GetPendingInvitation(ctx context.Context, token string) (entity.Invitation, error) {
+	return u.orgService.GetPendingInvitation(policy.AsSystem(ctx), token)
+}
+
+func (u *UseCase) AcceptInvitation(ctx context.Context, userId uuid.UUID, token string) (entity.Membership, error) {
+	return u.orgService.AcceptInvitation(policy.AsSystem(ctx), userId, token)
+}
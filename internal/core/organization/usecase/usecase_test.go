@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/organization/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService struct {
+	usecase.Service
+
+	inviteErr   error
+	invited     []string
+	memberships []entity.Membership
+	revoked     []uuid.UUID
+}
+
+func (s *fakeService) GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error) {
+	for _, m := range s.memberships {
+		if m.Organization.Id == orgId && m.UserId == userId {
+			return m, nil
+		}
+	}
+	return entity.Membership{}, core.ErrNotFound
+}
+
+func (s *fakeService) ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error) {
+	return []entity.Invitation{{Organization: entity.Organization{Id: orgId}}}, nil
+}
+
+func (s *fakeService) RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error {
+	s.revoked = append(s.revoked, invitationId)
+	return nil
+}
+
+func (s *fakeService) Invite(ctx context.Context, orgId, inviterId uuid.UUID, email, role string) (entity.Invitation, error) {
+	if s.inviteErr != nil {
+		return entity.Invitation{}, s.inviteErr
+	}
+	s.invited = append(s.invited, email)
+	return entity.Invitation{Email: email, Role: role}, nil
+}
+
+type fakeQuota struct {
+	used int64
+}
+
+func (q *fakeQuota) Consume(ctx context.Context, subject plan.Subject, key string, amount int64) error {
+	q.used += amount
+	return nil
+}
+
+func (q *fakeQuota) Refund(ctx context.Context, subject plan.Subject, key string, amount int64) error {
+	q.used -= amount
+	return nil
+}
+
+func newUseCase(svc *fakeService, quota *fakeQuota) *usecase.UseCase {
+	return usecase.New(svc, policy.New(false, usecase.Policies()...), quota)
+}
+
+func asMember(orgId, userId uuid.UUID, role string) context.Context {
+	return policy.WithPrincipal(context.Background(), policy.Principal{
+		UserId:  userId,
+		OrgId:   orgId,
+		OrgRole: role,
+	})
+}
+
+func TestInviteQuota(t *testing.T) {
+	tests := []struct {
+		name      string
+		inviteErr error
+		expected  int64
+	}{
+		{"should consume quota for sent invitations", nil, 1},
+		{"should refund quota when the invitation fails", core.ErrInvalid, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgId, userId := uuid.New(), uuid.New()
+			quota := &fakeQuota{}
+			u := newUseCase(&fakeService{inviteErr: tt.inviteErr}, quota)
+
+			_, err := u.Invite(asMember(orgId, userId, entity.MembershipRoleOwner), userId, orgId, "jane@example.com", entity.MembershipRoleMember)
+			if tt.inviteErr != nil {
+				assert.ErrorIs(t, err, tt.inviteErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, quota.used)
+		})
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	orgId, userId := uuid.New(), uuid.New()
+	svc := &fakeService{memberships: []entity.Membership{
+		{Organization: entity.Organization{Id: orgId}, UserId: userId, Role: entity.MembershipRoleMember},
+	}}
+	u := newUseCase(svc, &fakeQuota{})
+
+	tests := []struct {
+		name   string
+		userId uuid.UUID
+		orgId  uuid.UUID
+		err    error
+	}{
+		{"should switch to an organization the user belongs to", userId, orgId, nil},
+		{"should reject an organization the user does not belong to", userId, uuid.New(), core.ErrNotFound},
+		{"should reject another user's membership", uuid.New(), orgId, core.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := u.Switch(context.Background(), tt.userId, tt.orgId)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.orgId, m.Organization.Id)
+			assert.Equal(t, entity.MembershipRoleMember, m.Role)
+		})
+	}
+}
+
+func TestInvitationRoleChecks(t *testing.T) {
+	orgId, userId := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"should allow organization owner", asMember(orgId, userId, entity.MembershipRoleOwner), true},
+		{"should allow organization admin", asMember(orgId, userId, entity.MembershipRoleAdmin), true},
+		{"should forbid organization member", asMember(orgId, userId, entity.MembershipRoleMember), false},
+		{"should forbid admin of another organization", asMember(uuid.New(), userId, entity.MembershipRoleAdmin), false},
+		{"should forbid caller without an active organization", asMember(uuid.Nil, userId, ""), false},
+		{"should forbid caller without a principal", context.Background(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{}
+			quota := &fakeQuota{}
+			u := newUseCase(svc, quota)
+
+			_, inviteErr := u.Invite(tt.ctx, userId, orgId, "jane@example.com", entity.MembershipRoleMember)
+			_, listErr := u.ListInvitations(tt.ctx, orgId)
+			revokeErr := u.RevokeInvitation(tt.ctx, orgId, uuid.New())
+
+			if tt.allowed {
+				require.NoError(t, inviteErr)
+				require.NoError(t, listErr)
+				require.NoError(t, revokeErr)
+				assert.Len(t, svc.invited, 1)
+				assert.Len(t, svc.revoked, 1)
+				return
+			}
+			assert.ErrorIs(t, inviteErr, core.ErrForbidden)
+			assert.ErrorIs(t, listErr, core.ErrForbidden)
+			assert.ErrorIs(t, revokeErr, core.ErrForbidden)
+			assert.Empty(t, svc.invited)
+			assert.Empty(t, svc.revoked)
+			assert.Zero(t, quota.used)
+		})
+	}
+}
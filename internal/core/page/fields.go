@@ -0,0 +1,125 @@
+package page
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+const (
+	// IdField must be a sortable field of every list so it can break ties.
+	IdField = "id"
+)
+
+var (
+	ErrInvalidSort = fmt.Errorf("%w: invalid sort", core.ErrInvalid)
+)
+
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeInt
+	TypeBool
+	TypeTime
+	TypeUUID
+)
+
+// Field describes what clients may do with a field of a list. Sortable
+// fields must never be null.
+type Field struct {
+	Type       Type
+	Sortable   bool
+	Filterable bool
+}
+
+type Fields map[string]Field
+
+// Parse converts the string form of a value into the field's Go type:
+// string, int64, bool, time.Time or uuid.UUID.
+func (t Type) Parse(s string) (any, error) {
+	switch t {
+	case TypeString:
+		return s, nil
+	case TypeInt:
+		return strconv.ParseInt(s, 10, 64)
+	case TypeBool:
+		return strconv.ParseBool(s)
+	case TypeTime:
+		return time.Parse(time.RFC3339Nano, s)
+	case TypeUUID:
+		return uuid.Parse(s)
+	default:
+		return nil, fmt.Errorf("unknown field type %d", t)
+	}
+}
+
+func (t Type) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "integer"
+	case TypeBool:
+		return "boolean"
+	case TypeTime:
+		return "RFC 3339 timestamp"
+	case TypeUUID:
+		return "uuid"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSort reads a comma separated list of fields, each optionally
+// prefixed with - for descending order, e.g. "-created_at,email". An empty
+// string selects def. IdField is appended when missing.
+func ParseSort(raw string, fields Fields, def []Order) ([]Order, error) {
+	var orders []Order
+	if raw == "" {
+		orders = append(orders, def...)
+	} else {
+		seen := make(map[string]bool)
+		for part := range strings.SplitSeq(raw, ",") {
+			o := Order{Field: strings.TrimSpace(part)}
+			if name, ok := strings.CutPrefix(o.Field, "-"); ok {
+				o.Field, o.Desc = name, true
+			}
+
+			if f, ok := fields[o.Field]; !ok || !f.Sortable {
+				return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidSort, o.Field)
+			}
+			if seen[o.Field] {
+				return nil, fmt.Errorf("%w: %q is repeated", ErrInvalidSort, o.Field)
+			}
+			seen[o.Field] = true
+			orders = append(orders, o)
+		}
+	}
+
+	for _, o := range orders {
+		if o.Field == IdField {
+			return orders, nil
+		}
+	}
+
+	desc := len(orders) > 0 && orders[len(orders)-1].Desc
+	return append(orders, Order{Field: IdField, Desc: desc}), nil
+}
+
+// SortString is the inverse of ParseSort.
+func SortString(orders []Order) string {
+	parts := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.Desc {
+			parts = append(parts, "-"+o.Field)
+		} else {
+			parts = append(parts, o.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,428 @@
+package page
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+const (
+	MaxFilterLength      = 1024
+	MaxFilterComparisons = 32
+	maxFilterDepth       = 16
+)
+
+var (
+	ErrInvalidFilter = fmt.Errorf("%w: invalid filter", core.ErrInvalid)
+)
+
+type Op string
+
+const (
+	OpEq         Op = "eq"
+	OpNe         Op = "ne"
+	OpGt         Op = "gt"
+	OpGe         Op = "ge"
+	OpLt         Op = "lt"
+	OpLe         Op = "le"
+	OpContains   Op = "co"
+	OpStartsWith Op = "sw"
+	OpIn         Op = "in"
+)
+
+// Expr is a parsed filter: a Comparison, And, Or or Not.
+type Expr interface {
+	String() string
+}
+
+// Comparison holds a single value, or several for OpIn. A nil value, only
+// allowed with OpEq and OpNe, tests for null.
+type Comparison struct {
+	Field  string
+	Op     Op
+	Values []any
+}
+
+type And []Expr
+
+type Or []Expr
+
+type Not struct {
+	Expr Expr
+}
+
+func (c Comparison) String() string {
+	values := make([]string, 0, len(c.Values))
+	for _, v := range c.Values {
+		values = append(values, formatLiteral(v))
+	}
+	if c.Op == OpIn {
+		return fmt.Sprintf("%s in (%s)", c.Field, strings.Join(values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", c.Field, c.Op, values[0])
+}
+
+func (a And) String() string { return joinExprs(a, " and ") }
+
+func (o Or) String() string { return joinExprs(o, " or ") }
+
+func (n Not) String() string { return "not (" + n.Expr.String() + ")" }
+
+func joinExprs(exprs []Expr, sep string) string {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		parts = append(parts, "("+e.String()+")")
+	}
+	return strings.Join(parts, sep)
+}
+
+func formatLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quote(v)
+	case time.Time:
+		return quote(v.UTC().Format(time.RFC3339Nano))
+	case uuid.UUID:
+		return quote(v.String())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ParseFilter parses expressions such as
+//
+//	status eq "active" and (created_at ge "2025-01-01T00:00:00Z" or email sw "admin")
+//
+// Comparisons take the form <field> <op> <value>, where op is one of eq, ne,
+// gt, ge, lt, le, co (contains), sw (starts with) or in followed by a
+// parenthesized list. Values are double quoted strings, integers, true,
+// false or null, and are converted to the type of the field. Comparisons
+// combine with and, or, not and parentheses. An empty string yields a nil
+// Expr.
+func ParseFilter(raw string, fields Fields) (Expr, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	if len(raw) > MaxFilterLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidFilter, MaxFilterLength)
+	}
+
+	tokens, err := lex(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	e, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(raw string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case c == '"':
+			s, n, err := lexString(raw[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: at %d: %w", ErrInvalidFilter, i, err)
+			}
+			tokens = append(tokens, token{tokenString, s, i})
+			i += n
+		case c == '-' || isDigit(c):
+			start := i
+			i++
+			for i < len(raw) && isDigit(raw[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, raw[start:i], start})
+		case isIdentStart(c):
+			start := i
+			for i < len(raw) && (isIdentStart(raw[i]) || isDigit(raw[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, raw[start:i], start})
+		default:
+			return nil, fmt.Errorf("%w: at %d: unexpected %q", ErrInvalidFilter, i, c)
+		}
+	}
+	return append(tokens, token{tokenEOF, "end of filter", len(raw)}), nil
+}
+
+// lexString reads a double quoted string starting at s[0], where \" and \\
+// are the only escapes, and returns its value and length.
+func lexString(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(s) || (s[i+1] != '"' && s[i+1] != '\\') {
+				return "", 0, fmt.Errorf("invalid escape")
+			}
+			i++
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	fields      Fields
+	comparisons int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w: at %d: %s", ErrInvalidFilter, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) or(depth int) (Expr, error) {
+	e, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := Or{e}
+	for p.keyword("or") {
+		e, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) and(depth int) (Expr, error) {
+	e, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := And{e}
+	for p.keyword("and") {
+		e, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) unary(depth int) (Expr, error) {
+	if depth >= maxFilterDepth {
+		return nil, p.errorf(p.peek(), "nested too deeply")
+	}
+
+	if p.keyword("not") {
+		e, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{e}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		e, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected ) but found %q", t.text)
+		}
+		return e, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	ft := p.next()
+	if ft.kind != tokenIdent {
+		return nil, p.errorf(ft, "expected a field but found %q", ft.text)
+	}
+	field, ok := p.fields[ft.text]
+	if !ok || !field.Filterable {
+		return nil, p.errorf(ft, "cannot filter by %q", ft.text)
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxFilterComparisons {
+		return nil, p.errorf(ft, "more than %d comparisons", MaxFilterComparisons)
+	}
+
+	ot := p.next()
+	op := Op(strings.ToLower(ot.text))
+	if ot.kind != tokenIdent || !validOp(op) {
+		return nil, p.errorf(ot, "expected an operator but found %q", ot.text)
+	}
+	if (op == OpContains || op == OpStartsWith) && field.Type != TypeString {
+		return nil, p.errorf(ot, "%s only applies to string fields", op)
+	}
+	if (op == OpGt || op == OpGe || op == OpLt || op == OpLe) && (field.Type == TypeBool || field.Type == TypeUUID) {
+		return nil, p.errorf(ot, "%s does not apply to %s fields", op, field.Type)
+	}
+
+	c := Comparison{Field: ft.text, Op: op}
+	if op != OpIn {
+		v, err := p.value(field, op)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []any{v}
+		return c, nil
+	}
+
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, p.errorf(t, "expected ( after in but found %q", t.text)
+	}
+	for {
+		v, err := p.value(field, op)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = append(c.Values, v)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return c, nil
+		}
+		if t.kind != tokenComma {
+			return nil, p.errorf(t, "expected , or ) but found %q", t.text)
+		}
+	}
+}
+
+func (p *parser) value(field Field, op Op) (any, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenIdent && strings.EqualFold(t.text, "null"):
+		if op != OpEq && op != OpNe {
+			return nil, p.errorf(t, "null only applies to eq and ne")
+		}
+		return nil, nil
+	case t.kind == tokenIdent && (strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false")):
+		if field.Type != TypeBool {
+			return nil, p.errorf(t, "expected a %s", field.Type)
+		}
+		return strings.EqualFold(t.text, "true"), nil
+	case t.kind == tokenNumber:
+		if field.Type != TypeInt {
+			return nil, p.errorf(t, "expected a %s", field.Type)
+		}
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid integer %q", t.text)
+		}
+		return n, nil
+	case t.kind == tokenString:
+		if field.Type == TypeInt || field.Type == TypeBool {
+			return nil, p.errorf(t, "expected a %s", field.Type)
+		}
+		v, err := field.Type.Parse(t.text)
+		if err != nil {
+			return nil, p.errorf(t, "expected a %s", field.Type)
+		}
+		return v, nil
+	default:
+		return nil, p.errorf(t, "expected a value but found %q", t.text)
+	}
+}
+
+func validOp(op Op) bool {
+	switch op {
+	case OpEq, OpNe, OpGt, OpGe, OpLt, OpLe, OpContains, OpStartsWith, OpIn:
+		return true
+	default:
+		return false
+	}
+}
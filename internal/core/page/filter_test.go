@@ -0,0 +1,123 @@
+package page_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		raw  string
+		want page.Expr
+	}{
+		{"should parse empty filter", "  ", nil},
+		{
+			"should parse comparison",
+			`email eq "a@b.c"`,
+			page.Comparison{Field: "email", Op: page.OpEq, Values: []any{"a@b.c"}},
+		},
+		{
+			"should convert values to field type",
+			`created_at ge "2025-01-02T03:04:05Z"`,
+			page.Comparison{Field: "created_at", Op: page.OpGe, Values: []any{createdAt}},
+		},
+		{
+			"should parse integers and booleans",
+			`age lt -3 and verified eq true`,
+			page.And{
+				page.Comparison{Field: "age", Op: page.OpLt, Values: []any{int64(-3)}},
+				page.Comparison{Field: "verified", Op: page.OpEq, Values: []any{true}},
+			},
+		},
+		{
+			"should bind and tighter than or",
+			`age eq 1 or age eq 2 and email co "x"`,
+			page.Or{
+				page.Comparison{Field: "age", Op: page.OpEq, Values: []any{int64(1)}},
+				page.And{
+					page.Comparison{Field: "age", Op: page.OpEq, Values: []any{int64(2)}},
+					page.Comparison{Field: "email", Op: page.OpContains, Values: []any{"x"}},
+				},
+			},
+		},
+		{
+			"should parse parentheses and not",
+			`not (age eq 1 or age eq 2) AND email SW "ad\"min"`,
+			page.And{
+				page.Not{Expr: page.Or{
+					page.Comparison{Field: "age", Op: page.OpEq, Values: []any{int64(1)}},
+					page.Comparison{Field: "age", Op: page.OpEq, Values: []any{int64(2)}},
+				}},
+				page.Comparison{Field: "email", Op: page.OpStartsWith, Values: []any{`ad"min`}},
+			},
+		},
+		{
+			"should parse in lists",
+			`age in (1, 2,3)`,
+			page.Comparison{Field: "age", Op: page.OpIn, Values: []any{int64(1), int64(2), int64(3)}},
+		},
+		{
+			"should parse null",
+			`created_at ne null`,
+			page.Comparison{Field: "created_at", Op: page.OpNe, Values: []any{nil}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := page.ParseFilter(tt.raw, fields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e)
+		})
+	}
+}
+
+func TestParseFilterRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"should reject unknown field", `name eq "x"`},
+		{"should reject field that is not filterable", `id eq "x"`},
+		{"should reject unknown operator", `email like "x"`},
+		{"should reject value of another type", `age eq "1"`},
+		{"should reject invalid timestamp", `created_at gt "yesterday"`},
+		{"should reject contains on non string field", `age co 1`},
+		{"should reject ordering on booleans", `verified gt true`},
+		{"should reject null with ordering", `age gt null`},
+		{"should reject unterminated string", `email eq "x`},
+		{"should reject unbalanced parentheses", `(email eq "x"`},
+		{"should reject trailing tokens", `email eq "x" "y"`},
+		{"should reject dangling operator", `email eq "x" and`},
+		{"should reject unexpected character", `email = "x"`},
+		{"should reject deep nesting", strings.Repeat("(", 20) + `age eq 1` + strings.Repeat(")", 20)},
+		{"should reject too many comparisons", strings.Repeat(`age eq 1 or `, page.MaxFilterComparisons) + `age eq 1`},
+		{"should reject long filter", `email eq "` + strings.Repeat("x", page.MaxFilterLength) + `"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := page.ParseFilter(tt.raw, fields)
+			assert.ErrorIs(t, err, page.ErrInvalidFilter)
+			assert.ErrorIs(t, err, core.ErrInvalid)
+		})
+	}
+}
+
+func TestFilterStringRoundTrips(t *testing.T) {
+	raw := `not (age in (1, 2)) and (email co "a\"b\\c` + "\n" + `d" or created_at lt "2025-01-02T03:04:05Z")`
+
+	e, err := page.ParseFilter(raw, fields)
+	require.NoError(t, err)
+
+	again, err := page.ParseFilter(e.String(), fields)
+	require.NoError(t, err)
+	assert.Equal(t, e, again)
+}
@@ -0,0 +1,83 @@
+package page
+
+import (
+	"slices"
+)
+
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Cursor marks the row next to a page boundary by its sort key, one value
+// per order of the request that produced it.
+type Cursor struct {
+	Values []any
+	// Backward cursors select the rows before the boundary instead of the
+	// ones after it.
+	Backward bool
+}
+
+type Request struct {
+	Limit int
+	// Sort always ends with IdField so every row has a distinct position.
+	Sort []Order
+	// Filter is nil when every row matches.
+	Filter Expr
+	// Scope holds whatever else selects the rows besides Filter, so cursors
+	// are not replayed against another selection.
+	Scope  string
+	Cursor *Cursor
+}
+
+func (r Request) Backward() bool {
+	return r.Cursor != nil && r.Cursor.Backward
+}
+
+type Page[T any] struct {
+	Items []T
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+// New builds a page out of the rows a store fetched for req: up to Limit+1
+// of them, ordered in the direction of travel, so that the extra row tells
+// whether there is more in that direction. key returns the value of a sort
+// field for a row.
+func New[T any](rows []T, req Request, key func(item T, field string) any) Page[T] {
+	more := len(rows) > req.Limit
+	if more {
+		rows = rows[:req.Limit]
+	}
+	if req.Backward() {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+	}
+
+	p := Page[T]{Items: rows}
+	if len(rows) == 0 {
+		return p
+	}
+
+	hasNext := more
+	hasPrev := req.Cursor != nil
+	if req.Backward() {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		p.Next = &Cursor{Values: sortKey(rows[len(rows)-1], req.Sort, key)}
+	}
+	if hasPrev {
+		p.Prev = &Cursor{Values: sortKey(rows[0], req.Sort, key), Backward: true}
+	}
+	return p
+}
+
+func sortKey[T any](item T, orders []Order, key func(item T, field string) any) []any {
+	values := make([]any, 0, len(orders))
+	for _, o := range orders {
+		values = append(values, key(item, o.Field))
+	}
+	return values
+}
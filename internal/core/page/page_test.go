@@ -0,0 +1,164 @@
+package page_test
+
+import (
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sortById = []page.Order{{Field: page.IdField}}
+
+func key(item int, field string) any {
+	return item
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      []int
+		cursor    *page.Cursor
+		wantItems []int
+		wantNext  []any
+		wantPrev  []any
+	}{
+		{
+			"should not link first and only page",
+			[]int{1, 2},
+			nil,
+			[]int{1, 2},
+			nil,
+			nil,
+		},
+		{
+			"should link next page from first page",
+			[]int{1, 2, 3},
+			nil,
+			[]int{1, 2},
+			[]any{2},
+			nil,
+		},
+		{
+			"should link both ways from middle page",
+			[]int{3, 4, 5},
+			&page.Cursor{Values: []any{2}},
+			[]int{3, 4},
+			[]any{4},
+			[]any{3},
+		},
+		{
+			"should not link next page from last page",
+			[]int{5},
+			&page.Cursor{Values: []any{4}},
+			[]int{5},
+			nil,
+			[]any{5},
+		},
+		{
+			"should restore order when going backward",
+			[]int{4, 3, 2},
+			&page.Cursor{Values: []any{5}, Backward: true},
+			[]int{3, 4},
+			[]any{4},
+			[]any{3},
+		},
+		{
+			"should not link previous page when backward reaches the start",
+			[]int{2, 1},
+			&page.Cursor{Values: []any{3}, Backward: true},
+			[]int{1, 2},
+			[]any{2},
+			nil,
+		},
+		{
+			"should not link empty page",
+			nil,
+			&page.Cursor{Values: []any{9}},
+			nil,
+			nil,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := page.Request{Limit: 2, Sort: sortById, Cursor: tt.cursor}
+			p := page.New(tt.rows, req, key)
+
+			assert.Equal(t, tt.wantItems, p.Items)
+			if tt.wantNext == nil {
+				assert.Nil(t, p.Next)
+			} else {
+				require.NotNil(t, p.Next)
+				assert.Equal(t, tt.wantNext, p.Next.Values)
+				assert.False(t, p.Next.Backward)
+			}
+			if tt.wantPrev == nil {
+				assert.Nil(t, p.Prev)
+			} else {
+				require.NotNil(t, p.Prev)
+				assert.Equal(t, tt.wantPrev, p.Prev.Values)
+				assert.True(t, p.Prev.Backward)
+			}
+		})
+	}
+}
+
+func TestNewDoesNotReorderCallerRows(t *testing.T) {
+	rows := []int{3, 2, 1}
+	page.New(rows, page.Request{Limit: 5, Sort: sortById, Cursor: &page.Cursor{Backward: true}}, key)
+	assert.Equal(t, []int{3, 2, 1}, rows)
+}
+
+var fields = page.Fields{
+	page.IdField: {Type: page.TypeUUID, Sortable: true},
+	"email":      {Type: page.TypeString, Sortable: true, Filterable: true},
+	"created_at": {Type: page.TypeTime, Sortable: true, Filterable: true},
+	"age":        {Type: page.TypeInt, Filterable: true},
+	"verified":   {Type: page.TypeBool, Filterable: true},
+}
+
+func TestParseSort(t *testing.T) {
+	def := []page.Order{{Field: "created_at", Desc: true}}
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    []page.Order
+		wantErr bool
+	}{
+		{
+			"should use default and break ties by id",
+			"",
+			[]page.Order{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}},
+			false,
+		},
+		{
+			"should parse directions",
+			"email,-created_at",
+			[]page.Order{{Field: "email"}, {Field: "created_at", Desc: true}, {Field: "id", Desc: true}},
+			false,
+		},
+		{
+			"should keep explicit id",
+			"id,email",
+			[]page.Order{{Field: "id"}, {Field: "email"}},
+			false,
+		},
+		{"should reject unknown field", "name", nil, true},
+		{"should reject field that is not sortable", "age", nil, true},
+		{"should reject repeated field", "email,-email", nil, true},
+		{"should reject empty field", "email,", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := page.ParseSort(tt.raw, fields, def)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, page.ErrInvalidSort)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, orders)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+const (
+	SubjectUser         = "user"
+	SubjectOrganization = "organization"
+
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+	PeriodNone  = "none"
+
+	Unlimited int64 = -1
+
+	KeyAPIRequests = "api_requests"
+	KeyInvitations = "invitations"
+)
+
+var (
+	ErrNotInPlan = fmt.Errorf("%w: not included in the plan", core.ErrForbidden)
+)
+
+type Subject struct {
+	Type string
+	Id   uuid.UUID
+}
+
+func SubjectFrom(ctx context.Context) Subject {
+	p, _ := policy.PrincipalFrom(ctx)
+	if p.OrgId != uuid.Nil {
+		return Subject{Type: SubjectOrganization, Id: p.OrgId}
+	}
+	return Subject{Type: SubjectUser, Id: p.UserId}
+}
+
+type Entitlement struct {
+	Key    string
+	Quota  int64
+	Period string
+}
+
+type Plan struct {
+	Key          string
+	Name         string
+	Entitlements []Entitlement
+}
+
+func (p Plan) Entitlement(key string) (Entitlement, bool) {
+	for _, e := range p.Entitlements {
+		if e.Key == key {
+			return e, true
+		}
+	}
+	return Entitlement{}, false
+}
+
+type Usage struct {
+	Entitlement
+	Used     int64
+	ResetsAt *time.Time
+}
+
+type QuotaExceededError struct {
+	Key       string
+	Quota     int64
+	Used      int64
+	Requested int64
+	ResetsAt  *time.Time
+}
+
+func (err QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded for %s: %d of %d used", err.Key, err.Used, err.Quota)
+}
+
+func PeriodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	switch period {
+	case PeriodDay:
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	case PeriodMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Unix(0, 0).UTC()
+	}
+}
+
+func PeriodEnd(period string, now time.Time) *time.Time {
+	var end time.Time
+	switch period {
+	case PeriodDay:
+		end = PeriodStart(period, now).AddDate(0, 0, 1)
+	case PeriodMonth:
+		end = PeriodStart(period, now).AddDate(0, 1, 0)
+	default:
+		return nil
+	}
+	return &end
+}
+
+type Store interface {
+	GetPlan(ctx context.Context, subject Subject, defaultPlan string) (Plan, error)
+	SetPlan(ctx context.Context, subject Subject, planKey string) error
+	GetUsed(ctx context.Context, subject Subject, key string, periodStart time.Time) (int64, error)
+	Increment(ctx context.Context, subject Subject, key string, periodStart time.Time, amount, quota int64) (used int64, ok bool, err error)
+	Decrement(ctx context.Context, subject Subject, key string, periodStart time.Time, amount int64) error
+}
+
+type Service struct {
+	store       Store
+	defaultPlan string
+}
+
+func New(store Store, defaultPlan string) *Service {
+	return &Service{
+		store:       store,
+		defaultPlan: defaultPlan,
+	}
+}
+
+func (s *Service) Plan(ctx context.Context, subject Subject) (Plan, error) {
+	return s.store.GetPlan(ctx, subject, s.defaultPlan)
+}
+
+func (s *Service) SetPlan(ctx context.Context, subject Subject, planKey string) error {
+	if subject.Type != SubjectUser && subject.Type != SubjectOrganization {
+		return fmt.Errorf("%w: subject must be %s or %s", core.ErrInvalid, SubjectUser, SubjectOrganization)
+	}
+	return s.store.SetPlan(ctx, subject, planKey)
+}
+
+func (s *Service) Consume(ctx context.Context, subject Subject, key string, amount int64) error {
+	p, err := s.Plan(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("getting plan: %w", err)
+	}
+
+	now := time.Now()
+	e, ok := p.Entitlement(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotInPlan, key)
+	}
+
+	quota := e.Quota
+	if quota == Unlimited {
+		quota = math.MaxInt64
+	}
+
+	exceeded := QuotaExceededError{
+		Key:       key,
+		Quota:     e.Quota,
+		Requested: amount,
+		ResetsAt:  PeriodEnd(e.Period, now),
+	}
+	if amount > quota {
+		return exceeded
+	}
+
+	used, ok, err := s.store.Increment(ctx, subject, key, PeriodStart(e.Period, now), amount, quota)
+	if err != nil {
+		return fmt.Errorf("incrementing usage: %w", err)
+	}
+	if !ok {
+		exceeded.Used = used
+		return exceeded
+	}
+	return nil
+}
+
+// Refund gives back amount consumed in the current period, for work that
+// failed after its quota was taken.
+func (s *Service) Refund(ctx context.Context, subject Subject, key string, amount int64) error {
+	p, err := s.Plan(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("getting plan: %w", err)
+	}
+
+	e, ok := p.Entitlement(key)
+	if !ok {
+		return nil
+	}
+
+	err = s.store.Decrement(ctx, subject, key, PeriodStart(e.Period, time.Now()), amount)
+	if err != nil {
+		return fmt.Errorf("decrementing usage: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) Usage(ctx context.Context, subject Subject) (Plan, []Usage, error) {
+	p, err := s.Plan(ctx, subject)
+	if err != nil {
+		return Plan{}, nil, fmt.Errorf("getting plan: %w", err)
+	}
+
+	now := time.Now()
+	usage := make([]Usage, 0, len(p.Entitlements))
+	for _, e := range p.Entitlements {
+		used, err := s.store.GetUsed(ctx, subject, e.Key, PeriodStart(e.Period, now))
+		if err != nil {
+			return Plan{}, nil, fmt.Errorf("getting usage of %s: %w", e.Key, err)
+		}
+
+		usage = append(usage, Usage{
+			Entitlement: e,
+			Used:        used,
+			ResetsAt:    PeriodEnd(e.Period, now),
+		})
+	}
+	return p, usage, nil
+}
@@ -0,0 +1,185 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	plan plan.Plan
+	used map[string]int64
+}
+
+func (s *fakeStore) GetPlan(ctx context.Context, subject plan.Subject, defaultPlan string) (plan.Plan, error) {
+	return s.plan, nil
+}
+
+func (s *fakeStore) SetPlan(ctx context.Context, subject plan.Subject, planKey string) error {
+	return nil
+}
+
+func (s *fakeStore) GetUsed(ctx context.Context, subject plan.Subject, key string, periodStart time.Time) (int64, error) {
+	return s.used[key], nil
+}
+
+func (s *fakeStore) Increment(
+	ctx context.Context,
+	subject plan.Subject,
+	key string,
+	periodStart time.Time,
+	amount, quota int64,
+) (int64, bool, error) {
+	if s.used[key]+amount > quota {
+		return s.used[key], false, nil
+	}
+	s.used[key] += amount
+	return s.used[key], true, nil
+}
+
+func (s *fakeStore) Decrement(ctx context.Context, subject plan.Subject, key string, periodStart time.Time, amount int64) error {
+	s.used[key] = max(s.used[key]-amount, 0)
+	return nil
+}
+
+func TestPeriodStart(t *testing.T) {
+	now := time.Date(2025, time.March, 14, 15, 9, 26, 0, time.UTC)
+	at := func(month time.Month, day int) *time.Time {
+		t := time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		period    string
+		wantStart time.Time
+		wantEnd   *time.Time
+	}{
+		{
+			"should reset daily at midnight utc",
+			plan.PeriodDay,
+			time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+			at(time.March, 15),
+		},
+		{
+			"should reset monthly on the first",
+			plan.PeriodMonth,
+			time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			at(time.April, 1),
+		},
+		{
+			"should never reset without a period",
+			plan.PeriodNone,
+			time.Unix(0, 0).UTC(),
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStart, plan.PeriodStart(tt.period, now))
+			assert.Equal(t, tt.wantEnd, plan.PeriodEnd(tt.period, now))
+		})
+	}
+}
+
+func TestConsume(t *testing.T) {
+	subject := plan.Subject{Type: plan.SubjectUser, Id: uuid.New()}
+
+	tests := []struct {
+		name    string
+		quota   int64
+		used    int64
+		amount  int64
+		wantErr bool
+	}{
+		{"should allow usage within quota", 10, 9, 1, false},
+		{"should reject usage over quota", 10, 10, 1, true},
+		{"should reject amounts larger than the quota", 10, 0, 11, true},
+		{"should allow unlimited usage", plan.Unlimited, 1_000_000, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{
+				plan: plan.Plan{
+					Key: "free",
+					Entitlements: []plan.Entitlement{
+						{Key: plan.KeyAPIRequests, Quota: tt.quota, Period: plan.PeriodDay},
+					},
+				},
+				used: map[string]int64{plan.KeyAPIRequests: tt.used},
+			}
+			s := plan.New(store, "free")
+
+			err := s.Consume(context.Background(), subject, plan.KeyAPIRequests, tt.amount)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, tt.used+tt.amount, store.used[plan.KeyAPIRequests])
+				return
+			}
+
+			var quotaErr plan.QuotaExceededError
+			require.ErrorAs(t, err, &quotaErr)
+			assert.Equal(t, tt.quota, quotaErr.Quota)
+			assert.NotNil(t, quotaErr.ResetsAt)
+			assert.Equal(t, tt.used, store.used[plan.KeyAPIRequests])
+		})
+	}
+}
+
+func TestConsumeRejectsKeysOutsideThePlan(t *testing.T) {
+	s := plan.New(&fakeStore{plan: plan.Plan{Key: "free"}}, "free")
+
+	err := s.Consume(context.Background(), plan.Subject{Type: plan.SubjectUser}, plan.KeyInvitations, 1)
+
+	assert.ErrorIs(t, err, plan.ErrNotInPlan)
+	assert.ErrorIs(t, err, core.ErrForbidden)
+	assert.NotErrorAs(t, err, new(plan.QuotaExceededError))
+}
+
+func TestRefund(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     int64
+		amount   int64
+		expected int64
+	}{
+		{"should give back consumed usage", 5, 1, 4},
+		{"should not go below zero", 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{
+				plan: plan.Plan{
+					Key: "free",
+					Entitlements: []plan.Entitlement{
+						{Key: plan.KeyInvitations, Quota: 10, Period: plan.PeriodMonth},
+					},
+				},
+				used: map[string]int64{plan.KeyInvitations: tt.used},
+			}
+			s := plan.New(store, "free")
+
+			err := s.Refund(context.Background(), plan.Subject{Type: plan.SubjectUser}, plan.KeyInvitations, tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, store.used[plan.KeyInvitations])
+		})
+	}
+}
+
+func TestSubjectFrom(t *testing.T) {
+	userId := uuid.New()
+	orgId := uuid.New()
+
+	ctx := policy.WithPrincipal(context.Background(), policy.Principal{UserId: userId})
+	assert.Equal(t, plan.Subject{Type: plan.SubjectUser, Id: userId}, plan.SubjectFrom(ctx))
+
+	ctx = policy.WithPrincipal(context.Background(), policy.Principal{UserId: userId, OrgId: orgId})
+	assert.Equal(t, plan.Subject{Type: plan.SubjectOrganization, Id: orgId}, plan.SubjectFrom(ctx))
+}
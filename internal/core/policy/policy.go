@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+const (
+	AnyKind = "*"
+
+	ActionCreate = "create"
+	ActionRead   = "read"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionManage = "manage"
+)
+
+type Effect int
+
+const (
+	Abstain Effect = iota
+	Allow
+	Deny
+)
+
+func (e Effect) String() string {
+	switch e {
+	case Allow:
+		return "allow"
+	case Deny:
+		return "deny"
+	default:
+		return "abstain"
+	}
+}
+
+type Principal struct {
+	UserId  uuid.UUID
+	Roles   []string
+	OrgId   uuid.UUID
+	OrgRole string
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type Resource struct {
+	Kind       string
+	Id         uuid.UUID
+	OwnerId    uuid.UUID
+	OrgId      uuid.UUID
+	Attributes map[string]any
+}
+
+type Request struct {
+	Principal Principal
+	Action    string
+	Resource  Resource
+}
+
+type Rule struct {
+	Name    string
+	Kind    string
+	Actions []string
+	Check   func(ctx context.Context, req Request) Effect
+}
+
+func (r Rule) applies(req Request) bool {
+	if r.Kind != AnyKind && r.Kind != req.Resource.Kind {
+		return false
+	}
+	return len(r.Actions) == 0 || slices.Contains(r.Actions, req.Action)
+}
+
+type Reason struct {
+	Rule   string
+	Effect Effect
+}
+
+type Decision struct {
+	Allowed bool
+	Reasons []Reason
+}
+
+func (d Decision) String() string {
+	verdict := "denied"
+	if d.Allowed {
+		verdict = "allowed"
+	}
+	if len(d.Reasons) == 0 {
+		return verdict + ": no rule matched"
+	}
+	return fmt.Sprintf("%s: %v", verdict, d.Reasons)
+}
+
+type Engine struct {
+	rules   []Rule
+	explain bool
+}
+
+func New(explain bool, rules ...Rule) *Engine {
+	return &Engine{
+		rules:   rules,
+		explain: explain,
+	}
+}
+
+// Evaluate denies when any matching rule denies, allows when at least one
+// allows and denies by default when every rule abstains.
+func (e *Engine) Evaluate(ctx context.Context, req Request) Decision {
+	var d Decision
+	for _, rule := range e.rules {
+		if !rule.applies(req) {
+			continue
+		}
+
+		effect := rule.Check(ctx, req)
+		if effect == Abstain {
+			continue
+		}
+
+		d.Reasons = append(d.Reasons, Reason{Rule: rule.Name, Effect: effect})
+		if effect == Deny {
+			d.Allowed = false
+			return d
+		}
+		d.Allowed = true
+	}
+
+	return d
+}
+
+func (e *Engine) Authorize(ctx context.Context, action string, resource Resource) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return core.ErrForbidden
+	}
+
+	req := Request{
+		Principal: principal,
+		Action:    action,
+		Resource:  resource,
+	}
+	d := e.Evaluate(ctx, req)
+
+	if e.explain {
+		slog.Debug(
+			"policy decision",
+			slog.String("user_id", principal.UserId.String()),
+			slog.String("action", action),
+			slog.String("resource_kind", resource.Kind),
+			slog.String("resource_id", resource.Id.String()),
+			slog.String("decision", d.String()),
+		)
+	}
+
+	if !d.Allowed {
+		return fmt.Errorf("%w: %s %s", core.ErrForbidden, action, resource.Kind)
+	}
+	return nil
+}
+
+// Unexported key types keep other packages from forging a principal or the
+// system marker, which turns off row level security.
+type (
+	principalKey struct{}
+	systemKey    struct{}
+)
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+func AsSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
@@ -0,0 +1,158 @@
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+const kindDocument = "document"
+
+func TestEvaluate(t *testing.T) {
+	owner := uuid.New()
+	stranger := uuid.New()
+	org := uuid.New()
+
+	denyArchived := policy.Func(
+		"archived documents are read only",
+		kindDocument,
+		[]string{policy.ActionUpdate},
+		func(ctx context.Context, req policy.Request) policy.Effect {
+			if archived, _ := req.Resource.Attributes["archived"].(bool); archived {
+				return policy.Deny
+			}
+			return policy.Abstain
+		},
+	)
+
+	engine := policy.New(
+		false,
+		policy.OwnerCan(kindDocument, policy.ActionRead, policy.ActionUpdate),
+		policy.RoleCan("admin", policy.AnyKind, policy.ActionRead),
+		policy.OrgRoleCan("editor", kindDocument, policy.ActionUpdate),
+		denyArchived,
+	)
+
+	tests := []struct {
+		name     string
+		req      policy.Request
+		expected bool
+	}{
+		{
+			"should allow owner to read",
+			policy.Request{
+				Principal: policy.Principal{UserId: owner},
+				Action:    policy.ActionRead,
+				Resource:  policy.Resource{Kind: kindDocument, OwnerId: owner},
+			},
+			true,
+		},
+		{
+			"should deny stranger by default",
+			policy.Request{
+				Principal: policy.Principal{UserId: stranger},
+				Action:    policy.ActionRead,
+				Resource:  policy.Resource{Kind: kindDocument, OwnerId: owner},
+			},
+			false,
+		},
+		{
+			"should deny owner actions not granted",
+			policy.Request{
+				Principal: policy.Principal{UserId: owner},
+				Action:    policy.ActionDelete,
+				Resource:  policy.Resource{Kind: kindDocument, OwnerId: owner},
+			},
+			false,
+		},
+		{
+			"should allow role on any kind",
+			policy.Request{
+				Principal: policy.Principal{UserId: stranger, Roles: []string{"admin"}},
+				Action:    policy.ActionRead,
+				Resource:  policy.Resource{Kind: "invoice", OwnerId: owner},
+			},
+			true,
+		},
+		{
+			"should allow org role inside the org",
+			policy.Request{
+				Principal: policy.Principal{UserId: stranger, OrgId: org, OrgRole: "editor"},
+				Action:    policy.ActionUpdate,
+				Resource:  policy.Resource{Kind: kindDocument, OwnerId: owner, OrgId: org},
+			},
+			true,
+		},
+		{
+			"should deny org role outside the org",
+			policy.Request{
+				Principal: policy.Principal{UserId: stranger, OrgId: org, OrgRole: "editor"},
+				Action:    policy.ActionUpdate,
+				Resource:  policy.Resource{Kind: kindDocument, OwnerId: owner, OrgId: uuid.New()},
+			},
+			false,
+		},
+		{
+			"should let deny override allow",
+			policy.Request{
+				Principal: policy.Principal{UserId: owner},
+				Action:    policy.ActionUpdate,
+				Resource: policy.Resource{
+					Kind:       kindDocument,
+					OwnerId:    owner,
+					Attributes: map[string]any{"archived": true},
+				},
+			},
+			false,
+		},
+		{
+			"should not treat nil owner as a match",
+			policy.Request{
+				Principal: policy.Principal{},
+				Action:    policy.ActionRead,
+				Resource:  policy.Resource{Kind: kindDocument},
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := engine.Evaluate(context.Background(), tt.req)
+			assert.Equal(t, tt.expected, d.Allowed, d.String())
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	owner := uuid.New()
+	engine := policy.New(true, policy.OwnerCan(kindDocument, policy.ActionRead))
+	resource := policy.Resource{Kind: kindDocument, OwnerId: owner}
+
+	err := engine.Authorize(context.Background(), policy.ActionRead, resource)
+	assert.ErrorIs(t, err, core.ErrForbidden)
+
+	ctx := policy.WithPrincipal(context.Background(), policy.Principal{UserId: owner})
+	assert.NoError(t, engine.Authorize(ctx, policy.ActionRead, resource))
+
+	ctx = policy.WithPrincipal(context.Background(), policy.Principal{UserId: uuid.New()})
+	assert.ErrorIs(t, engine.Authorize(ctx, policy.ActionRead, resource), core.ErrForbidden)
+}
+
+func TestContextMarkers(t *testing.T) {
+	forged := context.WithValue(context.Background(), "system", true)
+	forged = context.WithValue(forged, "principal", policy.Principal{UserId: uuid.New()})
+
+	assert.False(t, policy.IsSystem(forged))
+	_, ok := policy.PrincipalFrom(forged)
+	assert.False(t, ok)
+
+	assert.True(t, policy.IsSystem(policy.AsSystem(context.Background())))
+	p := policy.Principal{UserId: uuid.New()}
+	got, ok := policy.PrincipalFrom(policy.WithPrincipal(context.Background(), p))
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+}
@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func Func(name string, kind string, actions []string, check func(ctx context.Context, req Request) Effect) Rule {
+	return Rule{
+		Name:    name,
+		Kind:    kind,
+		Actions: actions,
+		Check:   check,
+	}
+}
+
+func OwnerCan(kind string, actions ...string) Rule {
+	return Rule{
+		Name:    fmt.Sprintf("owner can %v %s", actions, kind),
+		Kind:    kind,
+		Actions: actions,
+		Check: func(ctx context.Context, req Request) Effect {
+			if req.Resource.OwnerId != uuid.Nil && req.Resource.OwnerId == req.Principal.UserId {
+				return Allow
+			}
+			return Abstain
+		},
+	}
+}
+
+func RoleCan(role string, kind string, actions ...string) Rule {
+	return Rule{
+		Name:    fmt.Sprintf("role %s can %v %s", role, actions, kind),
+		Kind:    kind,
+		Actions: actions,
+		Check: func(ctx context.Context, req Request) Effect {
+			if req.Principal.HasRole(role) {
+				return Allow
+			}
+			return Abstain
+		},
+	}
+}
+
+func OrgRoleCan(orgRole string, kind string, actions ...string) Rule {
+	return Rule{
+		Name:    fmt.Sprintf("org role %s can %v %s in their org", orgRole, actions, kind),
+		Kind:    kind,
+		Actions: actions,
+		Check: func(ctx context.Context, req Request) Effect {
+			p := req.Principal
+			if p.OrgId != uuid.Nil && p.OrgRole == orgRole && req.Resource.OrgId == p.OrgId {
+				return Allow
+			}
+			return Abstain
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+
+	PermissionProfileRead  = "profile:read"
+	PermissionProfileWrite = "profile:write"
+	PermissionUsersRead    = "users:read"
+	PermissionUsersManage  = "users:manage"
+	PermissionFlagsManage  = "flags:manage"
+	PermissionPlansManage  = "plans:manage"
+)
+
+type Store interface {
+	GetUserRoles(ctx context.Context, userId uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error)
+}
+
+type grants struct {
+	roles       []string
+	permissions map[string]struct{}
+	expiresAt   time.Time
+}
+
+type Resolver struct {
+	store Store
+	ttl   time.Duration
+
+	mu    sync.RWMutex
+	cache map[uuid.UUID]grants
+}
+
+func NewResolver(store Store, ttl time.Duration) *Resolver {
+	return &Resolver{
+		store: store,
+		ttl:   ttl,
+		cache: make(map[uuid.UUID]grants),
+	}
+}
+
+func (r *Resolver) Roles(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	g, err := r.grants(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return g.roles, nil
+}
+
+func (r *Resolver) HasPermissions(ctx context.Context, userId uuid.UUID, permissions ...string) (bool, error) {
+	g, err := r.grants(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range permissions {
+		if _, ok := g.permissions[p]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *Resolver) Invalidate(userId uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, userId)
+}
+
+func (r *Resolver) grants(ctx context.Context, userId uuid.UUID) (grants, error) {
+	now := time.Now()
+
+	r.mu.RLock()
+	g, ok := r.cache[userId]
+	r.mu.RUnlock()
+	if ok && now.Before(g.expiresAt) {
+		return g, nil
+	}
+
+	roles, err := r.store.GetUserRoles(ctx, userId)
+	if err != nil {
+		return grants{}, err
+	}
+
+	permissions, err := r.store.GetUserPermissions(ctx, userId)
+	if err != nil {
+		return grants{}, err
+	}
+
+	g = grants{
+		roles:       roles,
+		permissions: make(map[string]struct{}, len(permissions)),
+		expiresAt:   now.Add(r.ttl),
+	}
+	for _, p := range permissions {
+		g.permissions[p] = struct{}{}
+	}
+
+	r.mu.Lock()
+	for id, cached := range r.cache {
+		if now.After(cached.expiresAt) {
+			delete(r.cache, id)
+		}
+	}
+	r.cache[userId] = g
+	r.mu.Unlock()
+
+	return g, nil
+}
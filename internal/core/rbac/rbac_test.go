@@ -0,0 +1,77 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	calls       int
+	roles       []string
+	permissions []string
+}
+
+func (s *fakeStore) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	s.calls++
+	return s.roles, nil
+}
+
+func (s *fakeStore) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	return s.permissions, nil
+}
+
+func TestHasPermissions(t *testing.T) {
+	store := &fakeStore{
+		roles:       []string{rbac.RoleUser},
+		permissions: []string{rbac.PermissionProfileRead, rbac.PermissionProfileWrite},
+	}
+	r := rbac.NewResolver(store, time.Minute)
+	userId := uuid.New()
+
+	tests := []struct {
+		name        string
+		permissions []string
+		want        bool
+	}{
+		{
+			"should grant when user has every permission",
+			[]string{rbac.PermissionProfileRead, rbac.PermissionProfileWrite},
+			true,
+		},
+		{
+			"should deny when user misses one permission",
+			[]string{rbac.PermissionProfileRead, rbac.PermissionUsersManage},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.HasPermissions(context.Background(), userId, tt.permissions...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolverCachesUntilInvalidated(t *testing.T) {
+	store := &fakeStore{roles: []string{rbac.RoleUser}}
+	r := rbac.NewResolver(store, time.Minute)
+	userId := uuid.New()
+
+	for range 3 {
+		_, err := r.Roles(context.Background(), userId)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, store.calls)
+
+	r.Invalidate(userId)
+	_, err := r.Roles(context.Background(), userId)
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.calls)
+}
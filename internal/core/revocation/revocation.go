@@ -0,0 +1,32 @@
+package revocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevokedToken struct {
+	TokenId   string    `json:"jti"`
+	UserId    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RevokedUser struct {
+	UserId        uuid.UUID `json:"user_id"`
+	RevokedBefore time.Time `json:"revoked_before"`
+}
+
+type Event struct {
+	Token *RevokedToken `json:"token,omitempty"`
+	User  *RevokedUser  `json:"user,omitempty"`
+}
+
+type Store interface {
+	InsertToken(ctx context.Context, t RevokedToken) error
+	InsertUser(ctx context.Context, u RevokedUser) error
+	GetActive(ctx context.Context, now time.Time, since time.Time) ([]RevokedToken, []RevokedUser, error)
+	DeleteExpired(ctx context.Context, now time.Time, since time.Time) error
+	Listen(ctx context.Context, ready func(), handle func(Event)) error
+}
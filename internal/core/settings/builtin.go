@@ -0,0 +1,38 @@
+package settings
+
+const (
+	KeyTheme              = "theme"
+	KeyEmailNotifications = "email_notifications"
+	KeyItemsPerPage       = "items_per_page"
+	KeyWeekStart          = "week_start"
+)
+
+// NewDefaultRegistry returns a registry holding the settings the frontend
+// knows about.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(Definition{
+		Key:      KeyTheme,
+		Type:     TypeString,
+		Defaults: []Default{{Version: 1, Value: "system"}},
+		Validate: OneOf("system", "light", "dark"),
+	})
+	r.Register(Definition{
+		Key:      KeyEmailNotifications,
+		Type:     TypeBool,
+		Defaults: []Default{{Version: 1, Value: true}},
+	})
+	r.Register(Definition{
+		Key:      KeyItemsPerPage,
+		Type:     TypeInt,
+		Defaults: []Default{{Version: 1, Value: 25}},
+		Validate: Between(10, 100),
+	})
+	r.Register(Definition{
+		Key:      KeyWeekStart,
+		Type:     TypeString,
+		Defaults: []Default{{Version: 1, Value: "monday"}},
+		Validate: OneOf("monday", "sunday", "saturday"),
+	})
+	return r
+}
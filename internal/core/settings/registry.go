@@ -0,0 +1,204 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type Type string
+
+const (
+	TypeBool   Type = "bool"
+	TypeString Type = "string"
+	TypeInt    Type = "int"
+)
+
+// Validator checks a value that already has the setting's Go type: bool,
+// string or int64.
+type Validator func(value any) error
+
+// Default is the value a setting takes from registry version Version on.
+type Default struct {
+	Version int
+	Value   any
+}
+
+type Definition struct {
+	Key  string
+	Type Type
+	// Defaults lists the default per registry version, oldest first. A
+	// default is changed by appending a new entry, so users who saved their
+	// settings earlier keep the one they saw.
+	Defaults []Default
+	Validate Validator
+}
+
+type Registry struct {
+	mu      sync.RWMutex
+	defs    map[string]Definition
+	keys    []string
+	version int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		defs:    make(map[string]Definition),
+		version: 1,
+	}
+}
+
+// Register adds a setting. It panics on an invalid definition since those
+// are programming errors caught at startup.
+func (r *Registry) Register(d Definition) {
+	d.Defaults = slices.Clone(d.Defaults)
+	if err := d.check(); err != nil {
+		panic(fmt.Errorf("registering setting %q: %w", d.Key, err))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.defs[d.Key]; ok {
+		panic(fmt.Errorf("registering setting %q: already registered", d.Key))
+	}
+
+	r.defs[d.Key] = d
+	r.keys = append(r.keys, d.Key)
+	slices.Sort(r.keys)
+	r.version = max(r.version, d.Defaults[len(d.Defaults)-1].Version)
+}
+
+// Version is the latest defaults version across every setting.
+func (r *Registry) Version() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+func (r *Registry) Has(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.defs[key]
+	return ok
+}
+
+// Decode parses and validates a JSON value for the setting key.
+func (r *Registry) Decode(key string, raw json.RawMessage) (any, error) {
+	r.mu.RLock()
+	d, ok := r.defs[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSetting, key)
+	}
+
+	v, err := d.decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %w", ErrInvalidValue, key, err)
+	}
+	return v, nil
+}
+
+// Resolve returns the value of every registered setting, taking stored
+// values where they are still valid and the defaults of version otherwise.
+func (r *Registry) Resolve(stored map[string]json.RawMessage, version int) map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values := make(map[string]any, len(r.keys))
+	for _, key := range r.keys {
+		d := r.defs[key]
+		if raw, ok := stored[key]; ok {
+			if v, err := d.decode(raw); err == nil {
+				values[key] = v
+				continue
+			}
+		}
+		values[key] = d.defaultAt(version)
+	}
+	return values
+}
+
+// check validates the definition and normalizes its defaults to the types
+// Decode produces.
+func (d Definition) check() error {
+	if d.Key == "" {
+		return errors.New("missing key")
+	}
+	if len(d.Defaults) == 0 {
+		return errors.New("missing default")
+	}
+
+	for i, def := range d.Defaults {
+		if def.Version < 1 || (i > 0 && def.Version <= d.Defaults[i-1].Version) {
+			return errors.New("default versions must start at 1 and increase")
+		}
+
+		raw, err := json.Marshal(def.Value)
+		if err != nil {
+			return fmt.Errorf("encoding default: %w", err)
+		}
+		v, err := d.decode(raw)
+		if err != nil {
+			return fmt.Errorf("invalid default for version %d: %w", def.Version, err)
+		}
+		d.Defaults[i].Value = v
+	}
+	return nil
+}
+
+// defaultAt returns the newest default introduced at or before version.
+// Settings added after version fall back to their first default.
+func (d Definition) defaultAt(version int) any {
+	value := d.Defaults[0].Value
+	for _, def := range d.Defaults {
+		if def.Version > version {
+			break
+		}
+		value = def.Value
+	}
+	return value
+}
+
+func (d Definition) decode(raw json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, errors.New("is not valid json")
+	}
+
+	switch d.Type {
+	case TypeBool:
+		if _, ok := v.(bool); !ok {
+			return nil, errors.New("must be a boolean")
+		}
+	case TypeString:
+		if _, ok := v.(string); !ok {
+			return nil, errors.New("must be a string")
+		}
+	case TypeInt:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, errors.New("must be an integer")
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		v = i
+	default:
+		return nil, fmt.Errorf("unsupported type %q", d.Type)
+	}
+
+	if d.Validate != nil {
+		if err := d.Validate(v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
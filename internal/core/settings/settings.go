@@ -0,0 +1,104 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+var (
+	ErrUnknownSetting = fmt.Errorf("%w: unknown setting", core.ErrInvalid)
+	ErrInvalidValue   = fmt.Errorf("%w: invalid setting value", core.ErrInvalid)
+)
+
+// Stored is what a user saved: only the settings they changed, and the
+// defaults version in effect when they first saved anything.
+type Stored struct {
+	Values          map[string]json.RawMessage
+	DefaultsVersion int
+}
+
+type Settings struct {
+	Values          map[string]any
+	DefaultsVersion int
+}
+
+type Store interface {
+	// Get returns core.ErrNotFound when the user never saved settings.
+	Get(ctx context.Context, userId uuid.UUID) (Stored, error)
+	// Update merges set into the stored values and drops the unset keys.
+	// defaultsVersion is only recorded when the user has no settings yet.
+	Update(ctx context.Context, userId uuid.UUID, set map[string]json.RawMessage, unset []string, defaultsVersion int) (Stored, error)
+}
+
+type Service struct {
+	store    Store
+	registry *Registry
+}
+
+func New(store Store, registry *Registry) *Service {
+	return &Service{
+		store:    store,
+		registry: registry,
+	}
+}
+
+func (s *Service) Get(ctx context.Context, userId uuid.UUID) (Settings, error) {
+	stored, err := s.store.Get(ctx, userId)
+	if errors.Is(err, core.ErrNotFound) {
+		stored = Stored{DefaultsVersion: s.registry.Version()}
+	} else if err != nil {
+		return Settings{}, err
+	}
+
+	return s.resolve(stored), nil
+}
+
+// Update applies a partial update. Keys set to null go back to their
+// default and keys left out are untouched.
+func (s *Service) Update(ctx context.Context, userId uuid.UUID, patch map[string]json.RawMessage) (Settings, error) {
+	if len(patch) == 0 {
+		return s.Get(ctx, userId)
+	}
+
+	set := make(map[string]json.RawMessage, len(patch))
+	var unset []string
+	for key, raw := range patch {
+		if string(raw) == "null" {
+			if !s.registry.Has(key) {
+				return Settings{}, fmt.Errorf("%w %q", ErrUnknownSetting, key)
+			}
+			unset = append(unset, key)
+			continue
+		}
+
+		v, err := s.registry.Decode(key, raw)
+		if err != nil {
+			return Settings{}, err
+		}
+
+		canonical, err := json.Marshal(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("encoding setting %q: %w", key, err)
+		}
+		set[key] = canonical
+	}
+
+	stored, err := s.store.Update(ctx, userId, set, unset, s.registry.Version())
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return s.resolve(stored), nil
+}
+
+func (s *Service) resolve(stored Stored) Settings {
+	return Settings{
+		Values:          s.registry.Resolve(stored.Values, stored.DefaultsVersion),
+		DefaultsVersion: stored.DefaultsVersion,
+	}
+}
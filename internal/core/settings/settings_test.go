@@ -0,0 +1,259 @@
+package settings_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	stored map[uuid.UUID]settings.Stored
+}
+
+func (s *fakeStore) Get(ctx context.Context, userId uuid.UUID) (settings.Stored, error) {
+	stored, ok := s.stored[userId]
+	if !ok {
+		return settings.Stored{}, core.ErrNotFound
+	}
+	return stored, nil
+}
+
+func (s *fakeStore) Update(
+	ctx context.Context,
+	userId uuid.UUID,
+	set map[string]json.RawMessage,
+	unset []string,
+	defaultsVersion int,
+) (settings.Stored, error) {
+	stored, ok := s.stored[userId]
+	if !ok {
+		stored = settings.Stored{Values: make(map[string]json.RawMessage), DefaultsVersion: defaultsVersion}
+	}
+	maps.Copy(stored.Values, set)
+	for _, key := range unset {
+		delete(stored.Values, key)
+	}
+	s.stored[userId] = stored
+	return stored, nil
+}
+
+func newRegistry() *settings.Registry {
+	r := settings.NewRegistry()
+	r.Register(settings.Definition{
+		Key:      "theme",
+		Type:     settings.TypeString,
+		Defaults: []settings.Default{{Version: 1, Value: "light"}, {Version: 2, Value: "system"}},
+		Validate: settings.OneOf("system", "light", "dark"),
+	})
+	r.Register(settings.Definition{
+		Key:      "page_size",
+		Type:     settings.TypeInt,
+		Defaults: []settings.Default{{Version: 1, Value: 25}},
+		Validate: settings.Between(10, 100),
+	})
+	r.Register(settings.Definition{
+		Key:      "digest",
+		Type:     settings.TypeBool,
+		Defaults: []settings.Default{{Version: 3, Value: true}},
+	})
+	return r
+}
+
+func TestRegistryVersionIsLatestDefault(t *testing.T) {
+	assert.Equal(t, 3, newRegistry().Version())
+}
+
+func TestRegisterPanicsOnInvalidDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		def  settings.Definition
+	}{
+		{
+			"should reject missing key",
+			settings.Definition{Type: settings.TypeBool, Defaults: []settings.Default{{Version: 1, Value: true}}},
+		},
+		{
+			"should reject missing default",
+			settings.Definition{Key: "x", Type: settings.TypeBool},
+		},
+		{
+			"should reject default of another type",
+			settings.Definition{Key: "x", Type: settings.TypeBool, Defaults: []settings.Default{{Version: 1, Value: "yes"}}},
+		},
+		{
+			"should reject default failing validation",
+			settings.Definition{
+				Key:      "x",
+				Type:     settings.TypeInt,
+				Defaults: []settings.Default{{Version: 1, Value: 1}},
+				Validate: settings.Between(10, 20),
+			},
+		},
+		{
+			"should reject decreasing versions",
+			settings.Definition{
+				Key:      "x",
+				Type:     settings.TypeBool,
+				Defaults: []settings.Default{{Version: 2, Value: true}, {Version: 1, Value: false}},
+			},
+		},
+		{
+			"should reject duplicate key",
+			settings.Definition{Key: "theme", Type: settings.TypeString, Defaults: []settings.Default{{Version: 1, Value: "dark"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRegistry()
+			assert.Panics(t, func() { r.Register(tt.def) })
+		})
+	}
+}
+
+func TestRegistryDecode(t *testing.T) {
+	r := newRegistry()
+
+	tests := []struct {
+		name    string
+		key     string
+		raw     string
+		want    any
+		wantErr error
+	}{
+		{"should decode string", "theme", `"dark"`, "dark", nil},
+		{"should decode integer", "page_size", `50`, int64(50), nil},
+		{"should decode bool", "digest", `false`, false, nil},
+		{"should reject unknown key", "font", `"serif"`, nil, settings.ErrUnknownSetting},
+		{"should reject wrong type", "digest", `"false"`, nil, settings.ErrInvalidValue},
+		{"should reject fractional integer", "page_size", `20.5`, nil, settings.ErrInvalidValue},
+		{"should reject value failing validation", "theme", `"blue"`, nil, settings.ErrInvalidValue},
+		{"should reject out of range integer", "page_size", `1000`, nil, settings.ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := r.Decode(tt.key, json.RawMessage(tt.raw))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, core.ErrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestGetWithoutStoredSettingsUsesLatestDefaults(t *testing.T) {
+	s := settings.New(&fakeStore{stored: make(map[uuid.UUID]settings.Stored)}, newRegistry())
+
+	got, err := s.Get(context.Background(), uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, got.DefaultsVersion)
+	assert.Equal(t, map[string]any{"theme": "system", "page_size": int64(25), "digest": true}, got.Values)
+}
+
+func TestGetKeepsDefaultsOfStoredVersion(t *testing.T) {
+	userId := uuid.New()
+	store := &fakeStore{stored: map[uuid.UUID]settings.Stored{
+		userId: {
+			Values: map[string]json.RawMessage{
+				"page_size": json.RawMessage(`50`),
+				"removed":   json.RawMessage(`1`),
+			},
+			DefaultsVersion: 1,
+		},
+	}}
+	s := settings.New(store, newRegistry())
+
+	got, err := s.Get(context.Background(), userId)
+	require.NoError(t, err)
+
+	// theme keeps the version 1 default, digest did not exist at version 1
+	// and takes its first default, and unregistered keys are dropped.
+	assert.Equal(t, map[string]any{"theme": "light", "page_size": int64(50), "digest": true}, got.Values)
+}
+
+func TestGetIgnoresStoredValuesThatNoLongerValidate(t *testing.T) {
+	userId := uuid.New()
+	store := &fakeStore{stored: map[uuid.UUID]settings.Stored{
+		userId: {
+			Values:          map[string]json.RawMessage{"theme": json.RawMessage(`"sepia"`)},
+			DefaultsVersion: 3,
+		},
+	}}
+	s := settings.New(store, newRegistry())
+
+	got, err := s.Get(context.Background(), userId)
+	require.NoError(t, err)
+	assert.Equal(t, "system", got.Values["theme"])
+}
+
+func TestUpdateAppliesPartialChanges(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	store := &fakeStore{stored: make(map[uuid.UUID]settings.Stored)}
+	s := settings.New(store, newRegistry())
+
+	got, err := s.Update(ctx, userId, map[string]json.RawMessage{
+		"theme":     json.RawMessage(`"dark"`),
+		"page_size": json.RawMessage(`50`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dark", got.Values["theme"])
+	assert.Equal(t, int64(50), got.Values["page_size"])
+	assert.Equal(t, 3, store.stored[userId].DefaultsVersion)
+
+	got, err = s.Update(ctx, userId, map[string]json.RawMessage{
+		"theme": json.RawMessage(`null`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "system", got.Values["theme"])
+	assert.Equal(t, int64(50), got.Values["page_size"])
+	assert.NotContains(t, store.stored[userId].Values, "theme")
+}
+
+func TestUpdateRejectsInvalidPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   map[string]json.RawMessage
+		wantErr error
+	}{
+		{
+			"should reject unknown setting",
+			map[string]json.RawMessage{"font": json.RawMessage(`"serif"`)},
+			settings.ErrUnknownSetting,
+		},
+		{
+			"should reject resetting unknown setting",
+			map[string]json.RawMessage{"font": json.RawMessage(`null`)},
+			settings.ErrUnknownSetting,
+		},
+		{
+			"should reject invalid value",
+			map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`), "page_size": json.RawMessage(`5`)},
+			settings.ErrInvalidValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{stored: make(map[uuid.UUID]settings.Stored)}
+			s := settings.New(store, newRegistry())
+
+			_, err := s.Update(context.Background(), uuid.New(), tt.patch)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Empty(t, store.stored)
+		})
+	}
+}
+
+func TestNewDefaultRegistry(t *testing.T) {
+	assert.NotPanics(t, func() { settings.NewDefaultRegistry() })
+}
@@ -0,0 +1,35 @@
+package settings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+func OneOf(allowed ...string) Validator {
+	return func(value any) error {
+		if s, _ := value.(string); !slices.Contains(allowed, s) {
+			return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+		}
+		return nil
+	}
+}
+
+func Between(min, max int64) Validator {
+	return func(value any) error {
+		if n, _ := value.(int64); n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+func MaxLength(n int) Validator {
+	return func(value any) error {
+		if s, _ := value.(string); utf8.RuneCountInString(s) > n {
+			return fmt.Errorf("must be at most %d characters", n)
+		}
+		return nil
+	}
+}
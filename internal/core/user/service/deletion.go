@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+)
+
+const (
+	purgeBatchSize = 100
+)
+
+func (s Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.Transition(ctx, id, entity.UserStatusDeleted)
+}
+
+// RestoreByProvider reactivates the deleted account linked to the provider
+// identity when it is still within the deletion grace period. Accounts past
+// it are returned with the deleted status so callers can refuse them until
+// they are purged.
+func (s Service) RestoreByProvider(ctx context.Context, provider, providerUserId string) (entity.User, error) {
+	id, deletedAt, err := s.userStore.GetDeletedByProvider(ctx, provider, providerUserId)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if !deletedAt.After(time.Now().Add(-s.policy.DeletionGracePeriod)) {
+		return entity.User{Id: id, Status: entity.UserStatusDeleted}, nil
+	}
+
+	u, err := s.userStore.Restore(ctx, id, deletedAt)
+	if errors.Is(err, core.ErrNotFound) {
+		return entity.User{}, fmt.Errorf("%w: account status changed concurrently", core.ErrConflict)
+	} else if err != nil {
+		return entity.User{}, err
+	}
+	s.statuses.invalidate(id)
+
+	return u, nil
+}
+
+// PurgeDeleted hard deletes accounts whose grace period is over, in batches
+// until none are left, along with their avatar blobs.
+func (s Service) PurgeDeleted(ctx context.Context) (int64, error) {
+	var total int64
+	for {
+		purged, err := s.userStore.Purge(ctx, time.Now().Add(-s.policy.DeletionGracePeriod), purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, u := range purged {
+			s.avatars.DeletePurged(ctx, u.Id, u.AvatarVersion)
+		}
+
+		total += int64(len(purged))
+		if len(purged) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s Service) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PurgeDeleted(ctx)
+			if err != nil {
+				slog.Error(
+					"purging deleted users",
+					slog.Any("error", err),
+				)
+			}
+			if n > 0 {
+				slog.Info("purged deleted users", slog.Int64("count", n))
+			}
+		}
+	}
+}
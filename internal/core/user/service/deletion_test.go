@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *fakeStore) GetDeletedByProvider(ctx context.Context, provider, providerUserId string) (uuid.UUID, time.Time, error) {
+	for id, deletedAt := range s.deletedAt {
+		return id, deletedAt, nil
+	}
+	return uuid.Nil, time.Time{}, core.ErrNotFound
+}
+
+func (s *fakeStore) Restore(ctx context.Context, id uuid.UUID, deletedAt time.Time) (entity.User, error) {
+	if !s.deletedAt[id].Equal(deletedAt) {
+		return entity.User{}, core.ErrNotFound
+	}
+	delete(s.deletedAt, id)
+	s.statuses[id] = entity.UserStatusActive
+	return entity.User{Id: id, Status: entity.UserStatusActive}, nil
+}
+
+func (s *fakeStore) Purge(ctx context.Context, deletedBefore time.Time, limit int) ([]entity.PurgedUser, error) {
+	n := min(s.purgeable, int64(limit))
+	s.purgeable -= n
+
+	purged := make([]entity.PurgedUser, n)
+	for i := range purged {
+		purged[i] = entity.PurgedUser{Id: uuid.New(), AvatarVersion: "v1"}
+	}
+	return purged, nil
+}
+
+type fakeAvatars struct {
+	deleted []uuid.UUID
+}
+
+func (a *fakeAvatars) DeletePurged(ctx context.Context, userId uuid.UUID, version string) {
+	a.deleted = append(a.deleted, userId)
+}
+
+func TestRestoreByProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		deletedAgo time.Duration
+		expected   string
+	}{
+		{"should restore within the grace period", time.Minute, entity.UserStatusActive},
+		{"should keep accounts past the grace period deleted", 2 * time.Hour, entity.UserStatusDeleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := uuid.New()
+			store := &fakeStore{
+				statuses:  map[uuid.UUID]string{id: entity.UserStatusDeleted},
+				deletedAt: map[uuid.UUID]time.Time{id: time.Now().Add(-tt.deletedAgo)},
+			}
+			s := service.New(store, &fakeRevoker{}, &fakeAvatars{}, &fakeMailer{}, testPolicy)
+
+			u, err := s.RestoreByProvider(context.Background(), "google", "123")
+			require.NoError(t, err)
+			assert.Equal(t, id, u.Id)
+			assert.Equal(t, tt.expected, u.Status)
+			assert.Equal(t, tt.expected, store.statuses[id])
+		})
+	}
+}
+
+func TestRestoreByProviderWithoutDeletedAccount(t *testing.T) {
+	s := service.New(&fakeStore{}, &fakeRevoker{}, &fakeAvatars{}, &fakeMailer{}, testPolicy)
+
+	_, err := s.RestoreByProvider(context.Background(), "google", "123")
+	assert.ErrorIs(t, err, core.ErrNotFound)
+}
+
+func TestDeleteSignsOutEverywhere(t *testing.T) {
+	id := uuid.New()
+	store := &fakeStore{statuses: map[uuid.UUID]string{id: entity.UserStatusActive}}
+	revoker := &fakeRevoker{}
+	s := service.New(store, revoker, &fakeAvatars{}, &fakeMailer{}, testPolicy)
+
+	require.NoError(t, s.Delete(context.Background(), id))
+	assert.Equal(t, entity.UserStatusDeleted, store.statuses[id])
+	assert.Equal(t, []uuid.UUID{id}, store.deletedSessions)
+	assert.Equal(t, []uuid.UUID{id}, revoker.revoked)
+}
+
+func TestPurgeDeletedRunsInBatches(t *testing.T) {
+	store := &fakeStore{purgeable: 250}
+	avatars := &fakeAvatars{}
+	s := service.New(store, &fakeRevoker{}, avatars, &fakeMailer{}, testPolicy)
+
+	n, err := s.PurgeDeleted(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 250, n)
+	assert.Zero(t, store.purgeable)
+	assert.Len(t, avatars.deleted, 250)
+}
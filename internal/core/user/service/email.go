@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	appmail "github.com/joaovictorsl/go-backend-template/internal/mail"
+)
+
+var (
+	ErrEmailInUse = fmt.Errorf("%w: email is already in use", core.ErrConflict)
+)
+
+// RequestEmailChange mails a confirmation link to the new address and a
+// notice to the current one. The email only changes once the link is used.
+func (s Service) RequestEmailChange(ctx context.Context, userId uuid.UUID, newEmail string) error {
+	newEmail, err := appmail.NormalizeAddress(newEmail)
+	if err != nil {
+		return err
+	}
+
+	u, err := s.userStore.Get(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if newEmail == u.Email {
+		return fmt.Errorf("%w: new email must be different from the current one", core.ErrInvalid)
+	}
+
+	exists, err := s.userStore.EmailExists(ctx, newEmail)
+	if err != nil {
+		return fmt.Errorf("checking email availability: %w", err)
+	}
+	if exists {
+		return ErrEmailInUse
+	}
+
+	token, tokenHash, err := newEmailChangeToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.policy.EmailChangeTTL)
+	if err := s.userStore.InsertEmailChange(ctx, userId, newEmail, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, appmail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Confirm that you want to use this address for your account: %s\n\nThis link expires on %s.",
+			s.confirmURL(token),
+			expiresAt.UTC().Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("sending email change confirmation: %w", err)
+	}
+
+	err = s.mailer.Send(ctx, appmail.Message{
+		To:      u.Email,
+		Subject: "Your email address is about to change",
+		Body: fmt.Sprintf(
+			"A request was made to change the email address of your account to %s.\n\nIf this was not you, sign in and secure your account.",
+			newEmail,
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("sending email change notice: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange swaps the email of the user and ends every session other
+// than the one confirming. Access tokens are revoked up to the current second
+// so the caller can be issued a fresh one right away.
+func (s Service) ConfirmEmailChange(ctx context.Context, userId, sessionId uuid.UUID, token string) (entity.User, error) {
+	now := time.Now()
+	u, err := s.userStore.ConfirmEmailChange(ctx, userId, HashEmailChangeToken(token), now)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if err := s.userStore.DeleteSessionsExcept(ctx, userId, sessionId); err != nil {
+		return entity.User{}, fmt.Errorf("deleting other sessions: %w", err)
+	}
+
+	if err := s.sessionRevoker.RevokeUser(ctx, userId, now.Truncate(time.Second)); err != nil {
+		return entity.User{}, fmt.Errorf("revoking access tokens: %w", err)
+	}
+
+	return u, nil
+}
+
+func (s Service) confirmURL(token string) string {
+	return fmt.Sprintf("%s?token=%s", s.policy.EmailConfirmURL, url.QueryEscape(token))
+}
+
+func HashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newEmailChangeToken() (token string, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating email change token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashEmailChangeToken(token), nil
+}
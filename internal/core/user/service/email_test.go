@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *fakeStore) Get(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	return s.user, nil
+}
+
+func (s *fakeStore) EmailExists(ctx context.Context, email string) (bool, error) {
+	return slices.Contains(s.takenEmails, email), nil
+}
+
+func (s *fakeStore) InsertEmailChange(ctx context.Context, userId uuid.UUID, newEmail, tokenHash string, expiresAt time.Time) error {
+	s.emailChanges[tokenHash] = newEmail
+	return nil
+}
+
+func (s *fakeStore) ConfirmEmailChange(ctx context.Context, userId uuid.UUID, tokenHash string, now time.Time) (entity.User, error) {
+	newEmail, ok := s.emailChanges[tokenHash]
+	if !ok {
+		return entity.User{}, core.ErrNotFound
+	}
+	delete(s.emailChanges, tokenHash)
+	s.user.Email = newEmail
+	return s.user, nil
+}
+
+func (s *fakeStore) DeleteSessionsExcept(ctx context.Context, userId, sessionId uuid.UUID) error {
+	s.keptSession = sessionId
+	return nil
+}
+
+func newEmailChangeService() (*service.Service, *fakeStore, *fakeMailer, *fakeRevoker) {
+	store := &fakeStore{
+		user:         entity.User{Id: uuid.New(), Email: "old@example.com"},
+		takenEmails:  []string{"taken@example.com"},
+		emailChanges: make(map[string]string),
+	}
+	mailer := &fakeMailer{}
+	revoker := &fakeRevoker{}
+	return service.New(store, revoker, &fakeAvatars{}, mailer, testPolicy), store, mailer, revoker
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{"should accept a new address", " New@Example.com ", nil},
+		{"should reject an invalid address", "not an email", core.ErrInvalid},
+		{"should reject the current address", "OLD@example.com", core.ErrInvalid},
+		{"should reject an address in use", "taken@example.com", core.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, mailer, _ := newEmailChangeService()
+
+			err := s.RequestEmailChange(context.Background(), store.user.Id, tt.email)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, mailer.sent)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, mailer.sent, 2)
+			assert.Equal(t, "new@example.com", mailer.sent[0].To)
+			assert.Equal(t, "old@example.com", mailer.sent[1].To)
+			assert.NotContains(t, mailer.sent[1].Body, "token=")
+		})
+	}
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	s, store, mailer, revoker := newEmailChangeService()
+	sessionId := uuid.New()
+
+	require.NoError(t, s.RequestEmailChange(context.Background(), store.user.Id, "new@example.com"))
+	token := confirmationToken(t, mailer.sent[0].Body)
+
+	_, err := s.ConfirmEmailChange(context.Background(), store.user.Id, sessionId, "wrong")
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	u, err := s.ConfirmEmailChange(context.Background(), store.user.Id, sessionId, token)
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", u.Email)
+	assert.Equal(t, sessionId, store.keptSession)
+	assert.Equal(t, []uuid.UUID{store.user.Id}, revoker.revoked)
+	assert.Equal(t, revoker.before, revoker.before.Truncate(time.Second))
+
+	_, err = s.ConfirmEmailChange(context.Background(), store.user.Id, sessionId, token)
+	assert.ErrorIs(t, err, core.ErrNotFound)
+}
+
+func confirmationToken(t *testing.T, body string) string {
+	t.Helper()
+
+	start := strings.Index(body, testPolicy.EmailConfirmURL)
+	require.NotEqual(t, -1, start)
+	link := strings.Fields(body[start:])[0]
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return u.Query().Get("token")
+}
@@ -2,25 +2,121 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	appmail "github.com/joaovictorsl/go-backend-template/internal/mail"
 )
 
 type UserStore interface {
 	Get(context.Context, uuid.UUID) (entity.User, error)
+	Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error)
+	GetLinkedAccounts(ctx context.Context, userId uuid.UUID) ([]entity.LinkedAccount, error)
+	GetSessions(ctx context.Context, userId uuid.UUID) ([]entity.Session, error)
+	GetStatus(ctx context.Context, id uuid.UUID) (string, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error
+	DeleteSessions(ctx context.Context, userId uuid.UUID) error
+	UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (entity.User, error)
+	GetDeletedByProvider(ctx context.Context, provider, providerUserId string) (id uuid.UUID, deletedAt time.Time, err error)
+	Restore(ctx context.Context, id uuid.UUID, deletedAt time.Time) (entity.User, error)
+	Purge(ctx context.Context, deletedBefore time.Time, limit int) ([]entity.PurgedUser, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	InsertEmailChange(ctx context.Context, userId uuid.UUID, newEmail, tokenHash string, expiresAt time.Time) error
+	ConfirmEmailChange(ctx context.Context, userId uuid.UUID, tokenHash string, now time.Time) (entity.User, error)
+	DeleteSessionsExcept(ctx context.Context, userId, sessionId uuid.UUID) error
+}
+
+type SessionRevoker interface {
+	// RevokeUser revokes access tokens issued strictly before before, at
+	// whole second precision.
+	RevokeUser(ctx context.Context, userId uuid.UUID, before time.Time) error
+}
+
+type AvatarRemover interface {
+	DeletePurged(ctx context.Context, userId uuid.UUID, version string)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg appmail.Message) error
+}
+
+type Policy struct {
+	StatusCacheTTL      time.Duration
+	DeletionGracePeriod time.Duration
+	EmailChangeTTL      time.Duration
+	EmailConfirmURL     string
 }
 
 type Service struct {
-	userStore UserStore
+	userStore      UserStore
+	sessionRevoker SessionRevoker
+	avatars        AvatarRemover
+	mailer         Mailer
+	policy         Policy
+	statuses       *statusCache
 }
 
-func New(userStore UserStore) *Service {
+func New(
+	userStore UserStore,
+	sessionRevoker SessionRevoker,
+	avatars AvatarRemover,
+	mailer Mailer,
+	policy Policy,
+) *Service {
 	return &Service{
-		userStore: userStore,
+		userStore:      userStore,
+		sessionRevoker: sessionRevoker,
+		avatars:        avatars,
+		mailer:         mailer,
+		policy:         policy,
+		statuses:       newStatusCache(policy.StatusCacheTTL),
 	}
 }
 
 func (s Service) Get(ctx context.Context, id uuid.UUID) (entity.User, error) {
 	return s.userStore.Get(ctx, id)
 }
+
+func (s Service) UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (entity.User, error) {
+	return s.userStore.UpdateProfile(ctx, id, patch)
+}
+
+func (s Service) Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	return s.userStore.Search(ctx, filter)
+}
+
+func (s Service) GetDetails(ctx context.Context, id uuid.UUID) (entity.UserDetails, error) {
+	u, err := s.userStore.Get(ctx, id)
+	if err != nil {
+		return entity.UserDetails{}, err
+	}
+
+	accounts, err := s.userStore.GetLinkedAccounts(ctx, id)
+	if err != nil {
+		return entity.UserDetails{}, fmt.Errorf("getting linked accounts: %w", err)
+	}
+
+	sessions, err := s.userStore.GetSessions(ctx, id)
+	if err != nil {
+		return entity.UserDetails{}, fmt.Errorf("getting sessions: %w", err)
+	}
+
+	return entity.UserDetails{
+		User:           u,
+		LinkedAccounts: accounts,
+		Sessions:       sessions,
+	}, nil
+}
+
+func (s Service) SignOutEverywhere(ctx context.Context, id uuid.UUID) error {
+	if err := s.userStore.DeleteSessions(ctx, id); err != nil {
+		return fmt.Errorf("deleting sessions: %w", err)
+	}
+
+	if err := s.sessionRevoker.RevokeUser(ctx, id, time.Now()); err != nil {
+		return fmt.Errorf("revoking access tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+)
+
+var (
+	transitions = map[string][]string{
+		entity.UserStatusPending:   {entity.UserStatusActive, entity.UserStatusDeleted},
+		entity.UserStatusActive:    {entity.UserStatusSuspended, entity.UserStatusDeleted},
+		entity.UserStatusSuspended: {entity.UserStatusActive, entity.UserStatusDeleted},
+		entity.UserStatusDeleted:   {},
+	}
+)
+
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+func (s Service) Status(ctx context.Context, id uuid.UUID) (string, error) {
+	if status, ok := s.statuses.get(id, time.Now()); ok {
+		return status, nil
+	}
+
+	status, err := s.userStore.GetStatus(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	s.statuses.set(id, status, time.Now())
+	return status, nil
+}
+
+func (s Service) Transition(ctx context.Context, id uuid.UUID, to string) error {
+	from, err := s.userStore.GetStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: account cannot go from %s to %s", core.ErrConflict, from, to)
+	}
+
+	err = s.userStore.UpdateStatus(ctx, id, from, to)
+	if errors.Is(err, core.ErrNotFound) {
+		return fmt.Errorf("%w: account status changed concurrently", core.ErrConflict)
+	} else if err != nil {
+		return err
+	}
+	s.statuses.invalidate(id)
+
+	if to != entity.UserStatusActive {
+		return s.SignOutEverywhere(ctx, id)
+	}
+	return nil
+}
+
+// maxCachedStatuses bounds the cache; once it is full, statuses of users
+// not yet cached are read from the store until expired entries are swept.
+const maxCachedStatuses = 100_000
+
+type cachedStatus struct {
+	status    string
+	expiresAt time.Time
+}
+
+type statusCache struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[uuid.UUID]cachedStatus
+	sweptAt time.Time
+}
+
+func newStatusCache(ttl time.Duration) *statusCache {
+	return &statusCache{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cachedStatus),
+	}
+}
+
+func (c *statusCache) get(id uuid.UUID, now time.Time) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[id]
+	if !ok || !now.Before(e.expiresAt) {
+		return "", false
+	}
+	return e.status, true
+}
+
+func (c *statusCache) set(id uuid.UUID, status string, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Sweeping at most once per ttl keeps sets cheap while still dropping
+	// every entry within two ttls of its expiry.
+	if now.Sub(c.sweptAt) >= c.ttl {
+		for cachedId, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, cachedId)
+			}
+		}
+		c.sweptAt = now
+	}
+
+	if _, ok := c.entries[id]; !ok && len(c.entries) >= maxCachedStatuses {
+		return
+	}
+	c.entries[id] = cachedStatus{status: status, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *statusCache) invalidate(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
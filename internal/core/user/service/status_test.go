@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/service"
+	"github.com/joaovictorsl/go-backend-template/internal/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	service.UserStore
+
+	statuses        map[uuid.UUID]string
+	statusReads     int
+	deletedSessions []uuid.UUID
+	deletedAt       map[uuid.UUID]time.Time
+	purgeable       int64
+	user            entity.User
+	takenEmails     []string
+	emailChanges    map[string]string
+	keptSession     uuid.UUID
+}
+
+func (s *fakeStore) GetStatus(ctx context.Context, id uuid.UUID) (string, error) {
+	s.statusReads++
+	status, ok := s.statuses[id]
+	if !ok {
+		return "", core.ErrNotFound
+	}
+	return status, nil
+}
+
+func (s *fakeStore) UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	if s.statuses[id] != from {
+		return core.ErrNotFound
+	}
+	s.statuses[id] = to
+	return nil
+}
+
+func (s *fakeStore) DeleteSessions(ctx context.Context, userId uuid.UUID) error {
+	s.deletedSessions = append(s.deletedSessions, userId)
+	return nil
+}
+
+var testPolicy = service.Policy{
+	StatusCacheTTL:      time.Minute,
+	DeletionGracePeriod: time.Hour,
+	EmailChangeTTL:      time.Hour,
+	EmailConfirmURL:     "https://example.com/email/confirm",
+}
+
+type fakeMailer struct {
+	sent []mail.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+type fakeRevoker struct {
+	revoked []uuid.UUID
+	before  time.Time
+}
+
+func (r *fakeRevoker) RevokeUser(ctx context.Context, userId uuid.UUID, before time.Time) error {
+	r.revoked = append(r.revoked, userId)
+	r.before = before
+	return nil
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{entity.UserStatusPending, entity.UserStatusActive, true},
+		{entity.UserStatusPending, entity.UserStatusSuspended, false},
+		{entity.UserStatusActive, entity.UserStatusSuspended, true},
+		{entity.UserStatusActive, entity.UserStatusPending, false},
+		{entity.UserStatusSuspended, entity.UserStatusActive, true},
+		{entity.UserStatusSuspended, entity.UserStatusDeleted, true},
+		{entity.UserStatusDeleted, entity.UserStatusActive, false},
+		{entity.UserStatusActive, entity.UserStatusActive, false},
+		{"unknown", entity.UserStatusActive, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.expected, service.CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	id := uuid.New()
+	store := &fakeStore{statuses: map[uuid.UUID]string{id: entity.UserStatusActive}}
+	revoker := &fakeRevoker{}
+	s := service.New(store, revoker, &fakeAvatars{}, &fakeMailer{}, testPolicy)
+
+	require.NoError(t, s.Transition(context.Background(), id, entity.UserStatusSuspended))
+	assert.Equal(t, entity.UserStatusSuspended, store.statuses[id])
+	assert.Equal(t, []uuid.UUID{id}, store.deletedSessions)
+	assert.Equal(t, []uuid.UUID{id}, revoker.revoked)
+
+	err := s.Transition(context.Background(), id, entity.UserStatusPending)
+	assert.ErrorIs(t, err, core.ErrConflict)
+
+	require.NoError(t, s.Transition(context.Background(), id, entity.UserStatusActive))
+	assert.Len(t, revoker.revoked, 1)
+}
+
+func TestStatusIsCachedUntilTransition(t *testing.T) {
+	id := uuid.New()
+	store := &fakeStore{statuses: map[uuid.UUID]string{id: entity.UserStatusActive}}
+	s := service.New(store, &fakeRevoker{}, &fakeAvatars{}, &fakeMailer{}, testPolicy)
+
+	for range 3 {
+		status, err := s.Status(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, entity.UserStatusActive, status)
+	}
+	assert.Equal(t, 1, store.statusReads)
+
+	require.NoError(t, s.Transition(context.Background(), id, entity.UserStatusSuspended))
+
+	status, err := s.Status(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, entity.UserStatusSuspended, status)
+}
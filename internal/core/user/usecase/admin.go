@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/service"
+)
+
+const (
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 100
+)
+
+var (
+	// UserListFields are the fields admins can sort and filter the user
+	// list by.
+	UserListFields = page.Fields{
+		page.IdField:   {Type: page.TypeUUID, Sortable: true},
+		"email":        {Type: page.TypeString, Sortable: true, Filterable: true},
+		"status":       {Type: page.TypeString, Filterable: true},
+		"display_name": {Type: page.TypeString, Filterable: true},
+		"created_at":   {Type: page.TypeTime, Sortable: true, Filterable: true},
+		"updated_at":   {Type: page.TypeTime, Sortable: true, Filterable: true},
+	}
+	DefaultUserSort = []page.Order{{Field: "created_at", Desc: true}}
+)
+
+type AdminService interface {
+	Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error)
+	GetDetails(ctx context.Context, id uuid.UUID) (entity.UserDetails, error)
+	Transition(ctx context.Context, id uuid.UUID, to string) error
+	SignOutEverywhere(ctx context.Context, id uuid.UUID) error
+}
+
+type AdminUseCase struct {
+	userService AdminService
+	authorizer  Authorizer
+}
+
+func NewAdmin(userService AdminService, authorizer Authorizer) *AdminUseCase {
+	return &AdminUseCase{
+		userService: userService,
+		authorizer:  authorizer,
+	}
+}
+
+func (u *AdminUseCase) Search(ctx context.Context, filter entity.UserFilter) (page.Page[entity.User], error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionManage, policy.Resource{Kind: ResourceKind}); err != nil {
+		return page.Page[entity.User]{}, err
+	}
+
+	if filter.Page.Limit <= 0 {
+		filter.Page.Limit = DefaultSearchLimit
+	}
+	filter.Page.Limit = min(filter.Page.Limit, MaxSearchLimit)
+	if len(filter.Page.Sort) == 0 {
+		filter.Page.Sort, _ = page.ParseSort("", UserListFields, DefaultUserSort)
+	}
+
+	if filter.Status != "" && !service.IsValidStatus(filter.Status) {
+		return page.Page[entity.User]{}, fmt.Errorf("%w: unknown status %q", core.ErrInvalid, filter.Status)
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return page.Page[entity.User]{}, fmt.Errorf("%w: created_after must be before created_before", core.ErrInvalid)
+	}
+
+	users, err := u.userService.Search(ctx, filter)
+	if err != nil {
+		return page.Page[entity.User]{}, err
+	}
+
+	return page.New(users, filter.Page, userSortKey), nil
+}
+
+func userSortKey(u entity.User, field string) any {
+	switch field {
+	case "email":
+		return u.Email
+	case "created_at":
+		return u.CreatedAt
+	case "updated_at":
+		return u.UpdatedAt
+	default:
+		return u.Id
+	}
+}
+
+func (u *AdminUseCase) Get(ctx context.Context, id uuid.UUID) (entity.UserDetails, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionManage, userResource(id)); err != nil {
+		return entity.UserDetails{}, err
+	}
+
+	return u.userService.GetDetails(ctx, id)
+}
+
+func (u *AdminUseCase) Suspend(ctx context.Context, id uuid.UUID) error {
+	if err := u.authorizeManage(ctx, id); err != nil {
+		return err
+	}
+
+	return u.userService.Transition(ctx, id, entity.UserStatusSuspended)
+}
+
+func (u *AdminUseCase) Activate(ctx context.Context, id uuid.UUID) error {
+	if err := u.authorizeManage(ctx, id); err != nil {
+		return err
+	}
+
+	return u.userService.Transition(ctx, id, entity.UserStatusActive)
+}
+
+func (u *AdminUseCase) SignOut(ctx context.Context, id uuid.UUID) error {
+	if err := u.authorizeManage(ctx, id); err != nil {
+		return err
+	}
+
+	return u.userService.SignOutEverywhere(ctx, id)
+}
+
+func (u *AdminUseCase) authorizeManage(ctx context.Context, id uuid.UUID) error {
+	if err := u.authorizer.Authorize(ctx, policy.ActionManage, userResource(id)); err != nil {
+		return err
+	}
+
+	if p, _ := policy.PrincipalFrom(ctx); p.UserId == id {
+		return fmt.Errorf("%w: admins cannot manage their own account", core.ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAdminService struct {
+	usecase.AdminService
+
+	filter   entity.UserFilter
+	users    []entity.User
+	statuses map[uuid.UUID]string
+}
+
+func (s *fakeAdminService) Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	s.filter = filter
+	return s.users, nil
+}
+
+func (s *fakeAdminService) Transition(ctx context.Context, id uuid.UUID, status string) error {
+	s.statuses[id] = status
+	return nil
+}
+
+func newAdmin(t *testing.T) (*usecase.AdminUseCase, *fakeAdminService, context.Context, uuid.UUID) {
+	t.Helper()
+
+	svc := &fakeAdminService{statuses: make(map[uuid.UUID]string)}
+	engine := policy.New(false, usecase.Policies()...)
+	adminId := uuid.New()
+	ctx := policy.WithPrincipal(context.Background(), policy.Principal{
+		UserId: adminId,
+		Roles:  []string{rbac.RoleAdmin},
+	})
+	return usecase.NewAdmin(svc, engine), svc, ctx, adminId
+}
+
+func TestAdminSearchLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"should default limit", 0, usecase.DefaultSearchLimit},
+		{"should cap limit", 1000, usecase.MaxSearchLimit},
+		{"should keep limit within range", 5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, svc, ctx, _ := newAdmin(t)
+
+			_, err := u.Search(ctx, entity.UserFilter{Page: page.Request{Limit: tt.limit}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, svc.filter.Page.Limit)
+		})
+	}
+}
+
+func TestAdminSearchDefaultsSort(t *testing.T) {
+	u, svc, ctx, _ := newAdmin(t)
+
+	_, err := u.Search(ctx, entity.UserFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []page.Order{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}, svc.filter.Page.Sort)
+}
+
+func TestAdminSearchBuildsPage(t *testing.T) {
+	u, svc, ctx, _ := newAdmin(t)
+	svc.users = []entity.User{{Id: uuid.New()}, {Id: uuid.New()}, {Id: uuid.New()}}
+
+	p, err := u.Search(ctx, entity.UserFilter{Page: page.Request{Limit: 2}})
+	require.NoError(t, err)
+	assert.Len(t, p.Items, 2)
+	require.NotNil(t, p.Next)
+	assert.Equal(t, svc.users[1].Id, p.Next.Values[1])
+	assert.Nil(t, p.Prev)
+}
+
+func TestAdminSearchRejectsInvertedRange(t *testing.T) {
+	u, _, ctx, _ := newAdmin(t)
+	now := time.Now()
+
+	_, err := u.Search(ctx, entity.UserFilter{CreatedAfter: now, CreatedBefore: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, core.ErrInvalid)
+}
+
+func TestAdminSuspend(t *testing.T) {
+	u, svc, ctx, adminId := newAdmin(t)
+	userId := uuid.New()
+
+	require.NoError(t, u.Suspend(ctx, userId))
+	assert.Equal(t, entity.UserStatusSuspended, svc.statuses[userId])
+
+	assert.ErrorIs(t, u.Suspend(ctx, adminId), core.ErrInvalid)
+
+	notAdmin := policy.WithPrincipal(context.Background(), policy.Principal{UserId: uuid.New()})
+	assert.ErrorIs(t, u.Suspend(notAdmin, userId), core.ErrForbidden)
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+)
+
+const (
+	ResourceKind = "user"
+)
+
+func Policies() []policy.Rule {
+	return []policy.Rule{
+		policy.OwnerCan(ResourceKind, policy.ActionRead, policy.ActionUpdate, policy.ActionDelete),
+		policy.RoleCan(rbac.RoleAdmin, ResourceKind, policy.ActionRead, policy.ActionManage),
+	}
+}
+
+func userResource(id uuid.UUID) policy.Resource {
+	return policy.Resource{
+		Kind:    ResourceKind,
+		Id:      id,
+		OwnerId: id,
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+)
+
+const (
+	MaxDisplayNameLength = 100
+)
+
+// Language, optional script and optional region subtags of a BCP 47 tag,
+// e.g. "en", "pt-BR" or "zh-Hant-TW".
+var localeRegex = regexp.MustCompile(`^([a-zA-Z]{2,3})(?:-([a-zA-Z]{4}))?(?:-([a-zA-Z]{2}|[0-9]{3}))?$`)
+
+func normalizeProfilePatch(patch entity.ProfilePatch) (entity.ProfilePatch, error) {
+	if patch.DisplayName != nil {
+		name := strings.TrimSpace(*patch.DisplayName)
+		if utf8.RuneCountInString(name) > MaxDisplayNameLength {
+			return patch, fmt.Errorf("%w: display_name must have at most %d characters", core.ErrInvalid, MaxDisplayNameLength)
+		}
+		if strings.ContainsFunc(name, unicode.IsControl) {
+			return patch, fmt.Errorf("%w: display_name must not contain control characters", core.ErrInvalid)
+		}
+		patch.DisplayName = &name
+	}
+
+	if patch.Locale != nil && *patch.Locale != "" {
+		locale, err := normalizeLocale(*patch.Locale)
+		if err != nil {
+			return patch, err
+		}
+		patch.Locale = &locale
+	}
+
+	if patch.Timezone != nil && *patch.Timezone != "" {
+		if _, err := time.LoadLocation(*patch.Timezone); err != nil || *patch.Timezone == "Local" {
+			return patch, fmt.Errorf("%w: timezone must be an IANA time zone such as Europe/Lisbon", core.ErrInvalid)
+		}
+	}
+
+	return patch, nil
+}
+
+func normalizeLocale(locale string) (string, error) {
+	m := localeRegex.FindStringSubmatch(locale)
+	if m == nil {
+		return "", fmt.Errorf("%w: locale must be a language tag such as en or pt-BR", core.ErrInvalid)
+	}
+
+	tag := strings.ToLower(m[1])
+	if m[2] != "" {
+		tag += "-" + strings.ToUpper(m[2][:1]) + strings.ToLower(m[2][1:])
+	}
+	if m[3] != "" {
+		tag += "-" + strings.ToUpper(m[3])
+	}
+	return tag, nil
+}
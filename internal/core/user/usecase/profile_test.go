@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService struct {
+	usecase.Service
+
+	patch entity.ProfilePatch
+}
+
+func (s *fakeService) UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (entity.User, error) {
+	s.patch = patch
+	return entity.User{Id: id}, nil
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestUpdateProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    entity.ProfilePatch
+		expected entity.ProfilePatch
+		wantErr  bool
+	}{
+		{
+			"should trim display name",
+			entity.ProfilePatch{DisplayName: ptr("  Ada Lovelace ")},
+			entity.ProfilePatch{DisplayName: ptr("Ada Lovelace")},
+			false,
+		},
+		{
+			"should reject long display name",
+			entity.ProfilePatch{DisplayName: ptr(strings.Repeat("a", usecase.MaxDisplayNameLength+1))},
+			entity.ProfilePatch{},
+			true,
+		},
+		{
+			"should reject control characters in display name",
+			entity.ProfilePatch{DisplayName: ptr("Ada\nLovelace")},
+			entity.ProfilePatch{},
+			true,
+		},
+		{
+			"should canonicalize locale",
+			entity.ProfilePatch{Locale: ptr("zh-hant-tw")},
+			entity.ProfilePatch{Locale: ptr("zh-Hant-TW")},
+			false,
+		},
+		{
+			"should reject malformed locale",
+			entity.ProfilePatch{Locale: ptr("english")},
+			entity.ProfilePatch{},
+			true,
+		},
+		{
+			"should accept iana timezone",
+			entity.ProfilePatch{Timezone: ptr("America/Sao_Paulo")},
+			entity.ProfilePatch{Timezone: ptr("America/Sao_Paulo")},
+			false,
+		},
+		{
+			"should reject unknown timezone",
+			entity.ProfilePatch{Timezone: ptr("Mars/Olympus_Mons")},
+			entity.ProfilePatch{},
+			true,
+		},
+		{
+			"should allow clearing fields",
+			entity.ProfilePatch{Locale: ptr(""), Timezone: ptr("")},
+			entity.ProfilePatch{Locale: ptr(""), Timezone: ptr("")},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{}
+			u := usecase.New(svc, policy.New(false, usecase.Policies()...))
+			userId := uuid.New()
+			ctx := policy.WithPrincipal(context.Background(), policy.Principal{UserId: userId})
+
+			_, err := u.UpdateProfile(ctx, userId, tt.patch)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, core.ErrInvalid)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, svc.patch)
+		})
+	}
+}
+
+func TestUpdateProfileRejectsOtherUsers(t *testing.T) {
+	u := usecase.New(&fakeService{}, policy.New(false, usecase.Policies()...))
+	ctx := policy.WithPrincipal(context.Background(), policy.Principal{UserId: uuid.New()})
+
+	_, err := u.UpdateProfile(ctx, uuid.New(), entity.ProfilePatch{DisplayName: ptr("Eve")})
+	assert.ErrorIs(t, err, core.ErrForbidden)
+}
@@ -5,22 +5,74 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
 )
 
 type Service interface {
 	Get(ctx context.Context, id uuid.UUID) (entity.User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (entity.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	RequestEmailChange(ctx context.Context, userId uuid.UUID, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, userId, sessionId uuid.UUID, token string) (entity.User, error)
+}
+
+type Authorizer interface {
+	Authorize(ctx context.Context, action string, resource policy.Resource) error
 }
 
 type UseCase struct {
 	userService Service
+	authorizer  Authorizer
 }
 
-func New(userService Service) *UseCase {
+func New(userService Service, authorizer Authorizer) *UseCase {
 	return &UseCase{
 		userService: userService,
+		authorizer:  authorizer,
 	}
 }
 
 func (u *UseCase) Get(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionRead, userResource(id)); err != nil {
+		return entity.User{}, err
+	}
+
 	return u.userService.Get(ctx, id)
 }
+
+func (u *UseCase) UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (entity.User, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionUpdate, userResource(id)); err != nil {
+		return entity.User{}, err
+	}
+
+	patch, err := normalizeProfilePatch(patch)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return u.userService.UpdateProfile(ctx, id, patch)
+}
+
+func (u *UseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := u.authorizer.Authorize(ctx, policy.ActionDelete, userResource(id)); err != nil {
+		return err
+	}
+
+	return u.userService.Delete(ctx, id)
+}
+
+func (u *UseCase) RequestEmailChange(ctx context.Context, id uuid.UUID, newEmail string) error {
+	if err := u.authorizer.Authorize(ctx, policy.ActionUpdate, userResource(id)); err != nil {
+		return err
+	}
+
+	return u.userService.RequestEmailChange(ctx, id, newEmail)
+}
+
+func (u *UseCase) ConfirmEmailChange(ctx context.Context, id, sessionId uuid.UUID, token string) (entity.User, error) {
+	if err := u.authorizer.Authorize(ctx, policy.ActionUpdate, userResource(id)); err != nil {
+		return entity.User{}, err
+	}
+
+	return u.userService.ConfirmEmailChange(ctx, id, sessionId, token)
+}
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// LogMailer records that a message was sent without delivering it. Bodies
+// are never logged since they carry single use links.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(
+		ctx,
+		"sending email",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+	)
+	return nil
+}
+
+// NormalizeAddress accepts a bare address such as "Ada@Example.com" and
+// returns it lowercased. Display names are rejected.
+func NormalizeAddress(address string) (string, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil || addr.Name != "" {
+		return "", fmt.Errorf("%w: email is not a valid address", core.ErrInvalid)
+	}
+	return strings.ToLower(addr.Address), nil
+}
@@ -0,0 +1,44 @@
+package blob_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	l, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, l.Put(ctx, "exports/a.zip", strings.NewReader("archive")))
+
+	f, err := l.Open(ctx, "exports/a.zip")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	f.Close()
+	assert.Equal(t, "archive", string(data))
+
+	require.NoError(t, l.Delete(ctx, "exports/a.zip"))
+	_, err = l.Open(ctx, "exports/a.zip")
+	assert.ErrorIs(t, err, core.ErrNotFound)
+}
+
+func TestLocalRejectsKeysOutsideRoot(t *testing.T) {
+	l, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../a", "exports/../../a", "/etc/passwd", "exports/.hidden", ""} {
+		t.Run(key, func(t *testing.T) {
+			err := l.Put(context.Background(), key, strings.NewReader("x"))
+			assert.ErrorIs(t, err, blob.ErrInvalidKey)
+		})
+	}
+}
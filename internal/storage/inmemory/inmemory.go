@@ -1,6 +1,10 @@
 package inmemory
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+)
 
 type KVCache struct {
 	data *sync.Map
@@ -15,7 +19,7 @@ func New() *KVCache {
 func (c *KVCache) Get(key string) (string, error) {
 	v, ok := c.data.Load(key)
 	if !ok {
-		return "", nil
+		return "", core.ErrNotFound
 	}
 	return v.(string), nil
 }
@@ -0,0 +1,39 @@
+package avatar
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/get_avatar.sql
+	SQLGetAvatar string
+	//go:embed sql/set_avatar_version.sql
+	SQLSetAvatarVersion string
+	//go:embed sql/set_provider_picture.sql
+	SQLSetProviderPicture string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) Get(ctx context.Context, userId uuid.UUID) (a entity.Avatar, err error) {
+	err = r.DB.QueryRow(ctx, SQLGetAvatar, userId).Scan(&a.Version, &a.PictureURL)
+	return a, internal.MapError(err)
+}
+
+func (r *Repository) SetVersion(ctx context.Context, userId uuid.UUID, version string) (previous string, err error) {
+	err = r.DB.QueryRow(ctx, SQLSetAvatarVersion, userId, version).Scan(&previous)
+	return previous, internal.MapError(err)
+}
+
+func (r *Repository) SetProviderPicture(ctx context.Context, provider, providerUserId, pictureURL string) error {
+	_, err := r.DB.Exec(ctx, SQLSetProviderPicture, provider, providerUserId, pictureURL)
+	return internal.MapError(err)
+}
@@ -0,0 +1,14 @@
+SELECT
+  u.avatar_version,
+  COALESCE(
+    (
+      SELECT la.picture_url
+      FROM linked_accounts la
+      WHERE la.user_id = u.id AND la.picture_url <> ''
+      ORDER BY la.updated_at DESC
+      LIMIT 1
+    ),
+    ''
+  )
+FROM users u
+WHERE u.id=$1 AND u.deleted_at IS NULL;
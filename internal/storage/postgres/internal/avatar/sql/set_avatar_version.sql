@@ -0,0 +1,5 @@
+UPDATE users u
+SET avatar_version=$2, updated_at=NOW()
+FROM (SELECT id, avatar_version FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE) prev
+WHERE u.id = prev.id
+RETURNING prev.avatar_version;
@@ -0,0 +1,3 @@
+UPDATE linked_accounts
+SET picture_url=$3, updated_at=NOW()
+WHERE provider=$1 AND provider_user_id=$2 AND picture_url <> $3;
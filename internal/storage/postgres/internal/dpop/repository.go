@@ -0,0 +1,36 @@
+package dpop
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/new_proof.sql
+	SQLNewProof string
+	//go:embed sql/delete_expired_proofs.sql
+	SQLDeleteExpiredProofs string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+// Remember inserts key, or takes over an expired row with the same key. No
+// affected rows means the key is still remembered.
+func (r *Repository) Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	tag, err := r.DB.Exec(ctx, SQLNewProof, key, expiresAt)
+	if err != nil {
+		return false, internal.MapError(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.DB.Exec(ctx, SQLDeleteExpiredProofs, now)
+	return internal.MapError(err)
+}
@@ -0,0 +1,2 @@
+DELETE FROM dpop_proofs
+WHERE expires_at <= $1;
@@ -0,0 +1,5 @@
+INSERT INTO dpop_proofs (key, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE
+SET expires_at = EXCLUDED.expires_at
+WHERE dpop_proofs.expires_at <= NOW();
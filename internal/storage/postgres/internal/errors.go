@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/joaovictorsl/go-backend-template/internal/core"
 )
 
@@ -13,5 +14,17 @@ func MapError(err error) error {
 		err = fmt.Errorf("%w: %w", core.ErrNotFound, err)
 	}
 
+	// Writes outside the tenant of the connection fail row level security
+	// checks with insufficient_privilege.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42501" {
+		err = fmt.Errorf("%w: %w", core.ErrForbidden, err)
+	}
+
 	return err
 }
+
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+const (
+	// Running exports not finished within this time are assumed to belong to
+	// a worker that died and are handed out again.
+	claimTimeout = 30 * time.Minute
+
+	expiredBatchSize = 100
+)
+
+var (
+	//go:embed sql/new_data_export.sql
+	SQLNewDataExport string
+	//go:embed sql/get_data_export.sql
+	SQLGetDataExport string
+	//go:embed sql/claim_data_export.sql
+	SQLClaimDataExport string
+	//go:embed sql/complete_data_export.sql
+	SQLCompleteDataExport string
+	//go:embed sql/fail_data_export.sql
+	SQLFailDataExport string
+	//go:embed sql/get_expired_data_exports.sql
+	SQLGetExpiredDataExports string
+	//go:embed sql/delete_data_export.sql
+	SQLDeleteDataExport string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) Insert(ctx context.Context, userId uuid.UUID) (entity.DataExport, error) {
+	e, err := scanDataExport(r.DB.QueryRow(ctx, SQLNewDataExport, userId))
+	if internal.IsUniqueViolation(err) {
+		return e, fmt.Errorf("%w: an export is already in progress", core.ErrConflict)
+	}
+	return e, err
+}
+
+func (r *Repository) Get(ctx context.Context, userId, id uuid.UUID) (entity.DataExport, error) {
+	return scanDataExport(r.DB.QueryRow(ctx, SQLGetDataExport, userId, id))
+}
+
+func (r *Repository) ClaimPending(ctx context.Context) (entity.DataExport, error) {
+	return scanDataExport(r.DB.QueryRow(ctx, SQLClaimDataExport, time.Now().Add(-claimTimeout)))
+}
+
+func (r *Repository) Complete(ctx context.Context, id uuid.UUID, blobKey string, expiresAt time.Time) error {
+	return r.exec(ctx, SQLCompleteDataExport, id, blobKey, expiresAt)
+}
+
+func (r *Repository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.exec(ctx, SQLFailDataExport, id, reason)
+}
+
+// GetExpired also returns exports whose user was purged.
+func (r *Repository) GetExpired(ctx context.Context, now time.Time) ([]entity.DataExport, error) {
+	rows, err := r.DB.Query(ctx, SQLGetExpiredDataExports, now, expiredBatchSize)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	exports, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (e entity.DataExport, err error) {
+		err = row.Scan(&e.Id, &e.BlobKey)
+		return e, err
+	})
+	return exports, internal.MapError(err)
+}
+
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.exec(ctx, SQLDeleteDataExport, id)
+}
+
+func (r *Repository) exec(ctx context.Context, sql string, args ...any) error {
+	tag, err := r.DB.Exec(ctx, sql, args...)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanDataExport(row pgx.Row) (e entity.DataExport, err error) {
+	err = row.Scan(
+		&e.Id,
+		&e.UserId,
+		&e.Status,
+		&e.Error,
+		&e.BlobKey,
+		&e.CreatedAt,
+		&e.CompletedAt,
+		&e.ExpiresAt,
+	)
+	return e, internal.MapError(err)
+}
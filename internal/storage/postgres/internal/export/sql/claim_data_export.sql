@@ -0,0 +1,12 @@
+UPDATE data_exports
+SET status = 'running', claimed_at = NOW()
+WHERE id = (
+  SELECT id
+  FROM data_exports
+  WHERE user_id IS NOT NULL
+    AND (status = 'pending' OR (status = 'running' AND claimed_at < $1))
+  ORDER BY created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, status, error, blob_key, created_at, completed_at, expires_at;
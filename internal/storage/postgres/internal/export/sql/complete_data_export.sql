@@ -0,0 +1,3 @@
+UPDATE data_exports
+SET status = 'completed', blob_key = $2, completed_at = NOW(), expires_at = $3
+WHERE id=$1;
@@ -0,0 +1,2 @@
+DELETE FROM data_exports
+WHERE id = $1;
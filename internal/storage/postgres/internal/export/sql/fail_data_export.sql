@@ -0,0 +1,3 @@
+UPDATE data_exports
+SET status = 'failed', error = $2, completed_at = NOW()
+WHERE id=$1;
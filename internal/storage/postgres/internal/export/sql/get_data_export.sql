@@ -0,0 +1,3 @@
+SELECT id, user_id, status, error, blob_key, created_at, completed_at, expires_at
+FROM data_exports
+WHERE id=$2 AND user_id=$1;
@@ -0,0 +1,7 @@
+SELECT id, blob_key
+FROM data_exports
+WHERE expires_at <= $1
+   OR (status = 'failed' AND completed_at <= $1 - INTERVAL '7 days')
+   OR user_id IS NULL
+ORDER BY created_at
+LIMIT $2;
@@ -0,0 +1,3 @@
+INSERT INTO data_exports (user_id)
+VALUES ($1)
+RETURNING id, user_id, status, error, blob_key, created_at, completed_at, expires_at;
@@ -0,0 +1,76 @@
+package flags
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/flags"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/get_feature_flags.sql
+	SQLGetFeatureFlags string
+	//go:embed sql/upsert_feature_flag.sql
+	SQLUpsertFeatureFlag string
+	//go:embed sql/delete_feature_flag.sql
+	SQLDeleteFeatureFlag string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) GetAll(ctx context.Context) ([]flags.Flag, error) {
+	rows, err := r.DB.Query(ctx, SQLGetFeatureFlags)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	all, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (f flags.Flag, err error) {
+		err = row.Scan(
+			&f.Key,
+			&f.Description,
+			&f.Enabled,
+			&f.Rules.Percentage,
+			&f.Rules.Environments,
+			&f.Rules.Users,
+			&f.Rules.Organizations,
+			&f.UpdatedAt,
+		)
+		return f, err
+	})
+	return all, internal.MapError(err)
+}
+
+func (r *Repository) Upsert(ctx context.Context, f flags.Flag) (flags.Flag, error) {
+	rules := f.Rules.NonNil()
+	err := r.DB.QueryRow(
+		ctx,
+		SQLUpsertFeatureFlag,
+		f.Key,
+		f.Description,
+		f.Enabled,
+		rules.Percentage,
+		rules.Environments,
+		rules.Users,
+		rules.Organizations,
+	).Scan(&f.UpdatedAt)
+	return f, internal.MapError(err)
+}
+
+func (r *Repository) Delete(ctx context.Context, key string) error {
+	tag, err := r.DB.Exec(ctx, SQLDeleteFeatureFlag, key)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+DELETE FROM feature_flags
+WHERE key=$1;
@@ -0,0 +1,3 @@
+SELECT key, description, enabled, percentage, environments, user_ids, organization_ids, updated_at
+FROM feature_flags
+ORDER BY key ASC;
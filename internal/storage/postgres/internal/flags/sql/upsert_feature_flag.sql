@@ -0,0 +1,11 @@
+INSERT INTO feature_flags (key, description, enabled, percentage, environments, user_ids, organization_ids)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (key) DO UPDATE
+SET description = EXCLUDED.description,
+    enabled = EXCLUDED.enabled,
+    percentage = EXCLUDED.percentage,
+    environments = EXCLUDED.environments,
+    user_ids = EXCLUDED.user_ids,
+    organization_ids = EXCLUDED.organization_ids,
+    updated_at = NOW()
+RETURNING updated_at;
@@ -0,0 +1,147 @@
+package organization
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/new_invitation.sql
+	SQLNewInvitation string
+	//go:embed sql/revoke_pending_invitations_by_email.sql
+	SQLRevokePendingInvitationsByEmail string
+	//go:embed sql/get_invitations_by_organization.sql
+	SQLGetInvitationsByOrganization string
+	//go:embed sql/get_pending_invitation_by_token_hash.sql
+	SQLGetPendingInvitationByTokenHash string
+	//go:embed sql/revoke_invitation.sql
+	SQLRevokeInvitation string
+	//go:embed sql/accept_invitation.sql
+	SQLAcceptInvitation string
+	//go:embed sql/new_membership_if_absent.sql
+	SQLNewMembershipIfAbsent string
+)
+
+func (r *Repository) InsertInvitation(ctx context.Context, inv entity.Invitation, tokenHash string) (entity.Invitation, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return inv, internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, SQLRevokePendingInvitationsByEmail, inv.Organization.Id, inv.Email)
+	if err != nil {
+		return inv, internal.MapError(err)
+	}
+
+	err = tx.QueryRow(
+		ctx,
+		SQLNewInvitation,
+		inv.Organization.Id,
+		inv.Email,
+		inv.Role,
+		tokenHash,
+		inv.InvitedBy,
+		inv.ExpiresAt,
+	).Scan(&inv.Id, &inv.CreatedAt)
+	if err != nil {
+		return inv, internal.MapError(err)
+	}
+
+	return inv, internal.MapError(tx.Commit(ctx))
+}
+
+func (r *Repository) ListInvitations(ctx context.Context, orgId uuid.UUID) ([]entity.Invitation, error) {
+	rows, err := r.DB.Query(ctx, SQLGetInvitationsByOrganization, orgId)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	invitations, err := pgx.CollectRows(rows, scanInvitation)
+	return invitations, internal.MapError(err)
+}
+
+func (r *Repository) GetPendingInvitation(ctx context.Context, tokenHash string, now time.Time) (entity.Invitation, error) {
+	rows, err := r.DB.Query(ctx, SQLGetPendingInvitationByTokenHash, tokenHash, now)
+	if err != nil {
+		return entity.Invitation{}, internal.MapError(err)
+	}
+
+	inv, err := pgx.CollectExactlyOneRow(rows, scanInvitation)
+	return inv, internal.MapError(err)
+}
+
+func (r *Repository) RevokeInvitation(ctx context.Context, orgId, invitationId uuid.UUID) error {
+	tag, err := r.DB.Exec(ctx, SQLRevokeInvitation, invitationId, orgId)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) AcceptInvitation(
+	ctx context.Context,
+	orgId uuid.UUID,
+	tokenHash string,
+	userId uuid.UUID,
+	email string,
+	now time.Time,
+) (entity.Membership, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var role string
+	err = tx.QueryRow(ctx, SQLAcceptInvitation, orgId, tokenHash, userId, now, email).Scan(&role)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+
+	_, err = tx.Exec(ctx, SQLNewMembershipIfAbsent, orgId, userId, role)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+
+	rows, err := tx.Query(ctx, SQLGetMembership, orgId, userId)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+	m, err := pgx.CollectExactlyOneRow(rows, scanMembership)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+
+	return m, internal.MapError(tx.Commit(ctx))
+}
+
+func scanInvitation(row pgx.CollectableRow) (inv entity.Invitation, err error) {
+	err = row.Scan(
+		&inv.Id,
+		&inv.Organization.Id,
+		&inv.Organization.Name,
+		&inv.Organization.CreatedAt,
+		&inv.Organization.UpdatedAt,
+		&inv.Email,
+		&inv.Role,
+		&inv.InvitedBy,
+		&inv.ExpiresAt,
+		&inv.AcceptedAt,
+		&inv.RevokedAt,
+		&inv.CreatedAt,
+	)
+	return inv, err
+}
@@ -0,0 +1,88 @@
+package organization
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/new_organization.sql
+	SQLNewOrganization string
+	//go:embed sql/new_membership.sql
+	SQLNewMembership string
+	//go:embed sql/get_membership.sql
+	SQLGetMembership string
+	//go:embed sql/get_memberships_by_user.sql
+	SQLGetMembershipsByUser string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) Insert(ctx context.Context, name string, ownerId uuid.UUID, ownerRole string) (m entity.Membership, err error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return m, internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, SQLNewOrganization, name, ownerId).
+		Scan(
+			&m.Organization.Id,
+			&m.Organization.Name,
+			&m.Organization.CreatedAt,
+			&m.Organization.UpdatedAt,
+		)
+	if err != nil {
+		return m, internal.MapError(err)
+	}
+
+	m.UserId = ownerId
+	m.Role = ownerRole
+	err = tx.QueryRow(ctx, SQLNewMembership, m.Organization.Id, ownerId, ownerRole).Scan(&m.CreatedAt)
+	if err != nil {
+		return m, internal.MapError(err)
+	}
+
+	return m, internal.MapError(tx.Commit(ctx))
+}
+
+func (r *Repository) GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error) {
+	rows, err := r.DB.Query(ctx, SQLGetMembership, orgId, userId)
+	if err != nil {
+		return entity.Membership{}, internal.MapError(err)
+	}
+
+	m, err := pgx.CollectExactlyOneRow(rows, scanMembership)
+	return m, internal.MapError(err)
+}
+
+func (r *Repository) ListByUser(ctx context.Context, userId uuid.UUID) ([]entity.Membership, error) {
+	rows, err := r.DB.Query(ctx, SQLGetMembershipsByUser, userId)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	memberships, err := pgx.CollectRows(rows, scanMembership)
+	return memberships, internal.MapError(err)
+}
+
+func scanMembership(row pgx.CollectableRow) (m entity.Membership, err error) {
+	err = row.Scan(
+		&m.Organization.Id,
+		&m.Organization.Name,
+		&m.Organization.CreatedAt,
+		&m.Organization.UpdatedAt,
+		&m.UserId,
+		&m.Role,
+		&m.CreatedAt,
+	)
+	return m, err
+}
@@ -0,0 +1,4 @@
+UPDATE invitations
+SET accepted_at = $4, accepted_by = $3
+WHERE organization_id=$1 AND token_hash=$2 AND email=$5 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > $4
+RETURNING role;
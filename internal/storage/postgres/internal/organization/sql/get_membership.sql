@@ -0,0 +1,4 @@
+SELECT o.id, o.name, o.created_at, o.updated_at, m.user_id, m.role, m.created_at
+FROM memberships m
+JOIN organizations o ON m.organization_id = o.id
+WHERE m.organization_id=$1 AND m.user_id=$2;
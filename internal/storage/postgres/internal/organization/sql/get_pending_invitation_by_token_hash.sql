@@ -0,0 +1,4 @@
+SELECT i.id, o.id, o.name, o.created_at, o.updated_at, i.email, i.role, COALESCE(i.invited_by, '00000000-0000-0000-0000-000000000000'::uuid), i.expires_at, i.accepted_at, i.revoked_at, i.created_at
+FROM invitations i
+JOIN organizations o ON i.organization_id = o.id
+WHERE i.token_hash=$1 AND i.accepted_at IS NULL AND i.revoked_at IS NULL AND i.expires_at > $2;
@@ -0,0 +1,3 @@
+INSERT INTO invitations (organization_id, email, role, token_hash, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at;
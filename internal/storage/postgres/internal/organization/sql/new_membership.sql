@@ -0,0 +1,3 @@
+INSERT INTO memberships (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+RETURNING created_at;
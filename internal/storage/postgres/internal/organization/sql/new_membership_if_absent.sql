@@ -0,0 +1,3 @@
+INSERT INTO memberships (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO NOTHING;
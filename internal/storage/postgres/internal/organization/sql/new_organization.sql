@@ -0,0 +1,3 @@
+INSERT INTO organizations (name, created_by)
+VALUES ($1, $2)
+RETURNING id, name, created_at, updated_at;
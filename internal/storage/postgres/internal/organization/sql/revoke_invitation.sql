@@ -0,0 +1,3 @@
+UPDATE invitations
+SET revoked_at = NOW()
+WHERE id=$1 AND organization_id=$2 AND accepted_at IS NULL AND revoked_at IS NULL;
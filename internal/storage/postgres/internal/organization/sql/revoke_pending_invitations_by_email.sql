@@ -0,0 +1,3 @@
+UPDATE invitations
+SET revoked_at = NOW()
+WHERE organization_id=$1 AND email=$2 AND accepted_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,126 @@
+package plan
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/get_plan.sql
+	SQLGetPlan string
+	//go:embed sql/set_plan.sql
+	SQLSetPlan string
+	//go:embed sql/get_usage.sql
+	SQLGetUsage string
+	//go:embed sql/increment_usage.sql
+	SQLIncrementUsage string
+	//go:embed sql/decrement_usage.sql
+	SQLDecrementUsage string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) GetPlan(ctx context.Context, subject plan.Subject, defaultPlan string) (plan.Plan, error) {
+	rows, err := r.DB.Query(ctx, SQLGetPlan, subject.Type, subject.Id, defaultPlan)
+	if err != nil {
+		return plan.Plan{}, internal.MapError(err)
+	}
+	defer rows.Close()
+
+	var (
+		p     plan.Plan
+		found bool
+	)
+	for rows.Next() {
+		var (
+			key    *string
+			quota  *int64
+			period *string
+		)
+		if err := rows.Scan(&p.Key, &p.Name, &key, &quota, &period); err != nil {
+			return plan.Plan{}, internal.MapError(err)
+		}
+		found = true
+
+		if key != nil {
+			p.Entitlements = append(p.Entitlements, plan.Entitlement{
+				Key:    *key,
+				Quota:  *quota,
+				Period: *period,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return plan.Plan{}, internal.MapError(err)
+	}
+
+	if !found {
+		return plan.Plan{}, core.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (r *Repository) SetPlan(ctx context.Context, subject plan.Subject, planKey string) error {
+	tag, err := r.DB.Exec(ctx, SQLSetPlan, subject.Type, subject.Id, planKey)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) GetUsed(ctx context.Context, subject plan.Subject, key string, periodStart time.Time) (int64, error) {
+	var used int64
+	err := r.DB.QueryRow(ctx, SQLGetUsage, subject.Type, subject.Id, key, periodStart).Scan(&used)
+	return used, internal.MapError(err)
+}
+
+// Increment adds amount to the counter only while it stays within quota. When
+// it would not, the counter is left untouched and the current value returned.
+func (r *Repository) Increment(
+	ctx context.Context,
+	subject plan.Subject,
+	key string,
+	periodStart time.Time,
+	amount, quota int64,
+) (int64, bool, error) {
+	var used int64
+	err := r.DB.QueryRow(
+		ctx,
+		SQLIncrementUsage,
+		subject.Type,
+		subject.Id,
+		key,
+		periodStart,
+		amount,
+		quota,
+	).Scan(&used)
+	if errors.Is(err, pgx.ErrNoRows) {
+		used, err = r.GetUsed(ctx, subject, key, periodStart)
+		return used, false, err
+	} else if err != nil {
+		return 0, false, internal.MapError(err)
+	}
+
+	return used, true, nil
+}
+
+func (r *Repository) Decrement(ctx context.Context, subject plan.Subject, key string, periodStart time.Time, amount int64) error {
+	_, err := r.DB.Exec(ctx, SQLDecrementUsage, subject.Type, subject.Id, key, periodStart, amount)
+	return internal.MapError(err)
+}
@@ -0,0 +1,4 @@
+UPDATE usage_counters
+SET used = GREATEST(used - $5, 0),
+    updated_at = NOW()
+WHERE subject_type = $1 AND subject_id = $2 AND key = $3 AND period_start = $4;
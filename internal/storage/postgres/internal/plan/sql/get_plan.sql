@@ -0,0 +1,13 @@
+SELECT p.key, p.name, e.key, e.quota, e.period
+FROM plans p
+LEFT JOIN plan_entitlements e ON e.plan_id = p.id
+WHERE p.key = COALESCE(
+  (
+    SELECT sp.key
+    FROM subscriptions s
+    JOIN plans sp ON sp.id = s.plan_id
+    WHERE s.subject_type = $1 AND s.subject_id = $2
+  ),
+  $3
+)
+ORDER BY e.key;
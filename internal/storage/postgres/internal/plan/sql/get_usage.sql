@@ -0,0 +1,8 @@
+SELECT COALESCE(
+  (
+    SELECT used
+    FROM usage_counters
+    WHERE subject_type = $1 AND subject_id = $2 AND key = $3 AND period_start = $4
+  ),
+  0
+);
@@ -0,0 +1,7 @@
+INSERT INTO usage_counters (subject_type, subject_id, key, period_start, used)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (subject_type, subject_id, key, period_start) DO UPDATE
+SET used = usage_counters.used + EXCLUDED.used,
+    updated_at = NOW()
+WHERE usage_counters.used + EXCLUDED.used <= $6
+RETURNING used;
@@ -0,0 +1,7 @@
+INSERT INTO subscriptions (subject_type, subject_id, plan_id)
+SELECT $1, $2, id
+FROM plans
+WHERE key = $3
+ON CONFLICT (subject_type, subject_id) DO UPDATE
+SET plan_id = EXCLUDED.plan_id,
+    started_at = NOW();
@@ -0,0 +1,223 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Columns maps the fields a list exposes to the SQL expressions behind them.
+type Columns map[string]string
+
+// Builder renders the dynamic parts of a list query while collecting their
+// arguments, numbered after the ones the static part of the query uses.
+type Builder struct {
+	columns Columns
+	args    []any
+}
+
+func NewBuilder(columns Columns, args ...any) *Builder {
+	return &Builder{
+		columns: columns,
+		args:    args,
+	}
+}
+
+func (b *Builder) Args() []any {
+	return b.args
+}
+
+func (b *Builder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *Builder) column(field string) (string, error) {
+	col, ok := b.columns[field]
+	if !ok {
+		return "", fmt.Errorf("no column for field %q", field)
+	}
+	return col, nil
+}
+
+// Page renders the conditions, ordering and limit of req, to be appended to
+// a query whose WHERE clause it extends:
+//
+//	SELECT ... FROM users u WHERE u.deleted_at IS NULL <Page>
+//
+// It fetches one row past the limit, as page.New expects.
+func (b *Builder) Page(req page.Request) (string, error) {
+	filter, err := b.Filter(req.Filter)
+	if err != nil {
+		return "", err
+	}
+
+	keyset, err := b.Keyset(req.Sort, req.Cursor)
+	if err != nil {
+		return "", err
+	}
+
+	orderBy, err := b.OrderBy(req.Sort, req.Backward())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"AND (%s) AND %s ORDER BY %s LIMIT %s",
+		filter,
+		keyset,
+		orderBy,
+		b.arg(req.Limit+1),
+	), nil
+}
+
+// Filter renders e as a boolean SQL expression, TRUE when e is nil.
+func (b *Builder) Filter(e page.Expr) (string, error) {
+	switch e := e.(type) {
+	case nil:
+		return "TRUE", nil
+	case page.And:
+		return b.join(e, " AND ")
+	case page.Or:
+		return b.join(e, " OR ")
+	case page.Not:
+		inner, err := b.Filter(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case page.Comparison:
+		return b.comparison(e)
+	default:
+		return "", fmt.Errorf("unsupported filter expression %T", e)
+	}
+}
+
+func (b *Builder) join(exprs []page.Expr, sep string) (string, error) {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		part, err := b.Filter(e)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+part+")")
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func (b *Builder) comparison(c page.Comparison) (string, error) {
+	col, err := b.column(c.Field)
+	if err != nil {
+		return "", err
+	}
+
+	if len(c.Values) == 1 && c.Values[0] == nil {
+		switch c.Op {
+		case page.OpEq:
+			return col + " IS NULL", nil
+		case page.OpNe:
+			return col + " IS NOT NULL", nil
+		}
+	}
+
+	switch c.Op {
+	case page.OpEq:
+		return col + " = " + b.arg(c.Values[0]), nil
+	case page.OpNe:
+		return col + " IS DISTINCT FROM " + b.arg(c.Values[0]), nil
+	case page.OpGt:
+		return col + " > " + b.arg(c.Values[0]), nil
+	case page.OpGe:
+		return col + " >= " + b.arg(c.Values[0]), nil
+	case page.OpLt:
+		return col + " < " + b.arg(c.Values[0]), nil
+	case page.OpLe:
+		return col + " <= " + b.arg(c.Values[0]), nil
+	case page.OpContains, page.OpStartsWith:
+		v, ok := c.Values[0].(string)
+		if !ok {
+			return "", fmt.Errorf("filter operator %q needs a string, got %T", c.Op, c.Values[0])
+		}
+		if c.Op == page.OpContains {
+			return col + " ILIKE '%' || " + b.arg(EscapeLike(v)) + "::text || '%'", nil
+		}
+		return col + " ILIKE " + b.arg(EscapeLike(v)) + "::text || '%'", nil
+	case page.OpIn:
+		args := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			args = append(args, b.arg(v))
+		}
+		return col + " IN (" + strings.Join(args, ", ") + ")", nil
+	default:
+		return "", fmt.Errorf("unsupported filter operator %q", c.Op)
+	}
+}
+
+// Keyset renders the condition selecting the rows past cursor in the order
+// given by orders, TRUE when cursor is nil. For orders a, -b it expands to
+//
+//	(a > $1) OR (a = $1 AND b < $2)
+//
+// with the comparisons flipped for backward cursors.
+func (b *Builder) Keyset(orders []page.Order, cursor *page.Cursor) (string, error) {
+	if cursor == nil {
+		return "TRUE", nil
+	}
+	if len(cursor.Values) != len(orders) {
+		return "", fmt.Errorf("cursor has %d values for %d sort fields", len(cursor.Values), len(orders))
+	}
+
+	cols := make([]string, 0, len(orders))
+	args := make([]string, 0, len(orders))
+	for i, o := range orders {
+		col, err := b.column(o.Field)
+		if err != nil {
+			return "", err
+		}
+		cols = append(cols, col)
+		args = append(args, b.arg(cursor.Values[i]))
+	}
+
+	branches := make([]string, 0, len(orders))
+	for i, o := range orders {
+		terms := make([]string, 0, i+1)
+		for j := range i {
+			terms = append(terms, cols[j]+" = "+args[j])
+		}
+
+		op := ">"
+		if o.Desc != cursor.Backward {
+			op = "<"
+		}
+		terms = append(terms, cols[i]+" "+op+" "+args[i])
+		branches = append(branches, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", nil
+}
+
+// OrderBy renders the ORDER BY list for orders, reversed when fetching
+// backward.
+func (b *Builder) OrderBy(orders []page.Order, backward bool) (string, error) {
+	parts := make([]string, 0, len(orders))
+	for _, o := range orders {
+		col, err := b.column(o.Field)
+		if err != nil {
+			return "", err
+		}
+
+		if o.Desc != backward {
+			parts = append(parts, col+" DESC")
+		} else {
+			parts = append(parts, col+" ASC")
+		}
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// EscapeLike escapes the LIKE wildcards in s so it matches literally.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
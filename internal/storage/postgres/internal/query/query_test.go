@@ -0,0 +1,150 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var columns = query.Columns{
+	"id":         "u.id",
+	"email":      "u.email",
+	"created_at": "u.created_at",
+	"age":        "u.age",
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     page.Expr
+		wantSQL  string
+		wantArgs []any
+	}{
+		{"should match everything without filter", nil, "TRUE", []any{"static"}},
+		{
+			"should render comparisons after existing args",
+			page.And{
+				page.Comparison{Field: "age", Op: page.OpGe, Values: []any{int64(18)}},
+				page.Not{Expr: page.Comparison{Field: "email", Op: page.OpNe, Values: []any{"a"}}},
+			},
+			"(u.age >= $2) AND (NOT (u.email IS DISTINCT FROM $3))",
+			[]any{"static", int64(18), "a"},
+		},
+		{
+			"should render null checks without args",
+			page.Or{
+				page.Comparison{Field: "age", Op: page.OpEq, Values: []any{nil}},
+				page.Comparison{Field: "age", Op: page.OpNe, Values: []any{nil}},
+			},
+			"(u.age IS NULL) OR (u.age IS NOT NULL)",
+			[]any{"static"},
+		},
+		{
+			"should escape like patterns",
+			page.Or{
+				page.Comparison{Field: "email", Op: page.OpContains, Values: []any{"50%_off"}},
+				page.Comparison{Field: "email", Op: page.OpStartsWith, Values: []any{`a\b`}},
+			},
+			`(u.email ILIKE '%' || $2::text || '%') OR (u.email ILIKE $3::text || '%')`,
+			[]any{"static", `50\%\_off`, `a\\b`},
+		},
+		{
+			"should render in lists",
+			page.Comparison{Field: "age", Op: page.OpIn, Values: []any{int64(1), int64(2)}},
+			"u.age IN ($2, $3)",
+			[]any{"static", int64(1), int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(columns, "static")
+
+			sql, err := b.Filter(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, tt.wantArgs, b.Args())
+		})
+	}
+}
+
+func TestFilterRejectsUnmappedField(t *testing.T) {
+	b := query.NewBuilder(columns)
+
+	_, err := b.Filter(page.Comparison{Field: "name", Op: page.OpEq, Values: []any{"x"}})
+	assert.Error(t, err)
+}
+
+func TestFilterRejectsNonStringPattern(t *testing.T) {
+	b := query.NewBuilder(columns)
+
+	_, err := b.Filter(page.Comparison{Field: "age", Op: page.OpContains, Values: []any{int64(1)}})
+	assert.Error(t, err)
+}
+
+func TestKeyset(t *testing.T) {
+	now := time.Now()
+	orders := []page.Order{{Field: "created_at", Desc: true}, {Field: "email"}, {Field: "id"}}
+
+	tests := []struct {
+		name    string
+		cursor  *page.Cursor
+		wantSQL string
+	}{
+		{"should match everything without cursor", nil, "TRUE"},
+		{
+			"should select rows after cursor",
+			&page.Cursor{Values: []any{now, "a", "1"}},
+			"((u.created_at < $1) OR (u.created_at = $1 AND u.email > $2) OR (u.created_at = $1 AND u.email = $2 AND u.id > $3))",
+		},
+		{
+			"should select rows before backward cursor",
+			&page.Cursor{Values: []any{now, "a", "1"}, Backward: true},
+			"((u.created_at > $1) OR (u.created_at = $1 AND u.email < $2) OR (u.created_at = $1 AND u.email = $2 AND u.id < $3))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(columns)
+
+			sql, err := b.Keyset(orders, tt.cursor)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sql)
+			if tt.cursor != nil {
+				assert.Equal(t, tt.cursor.Values, b.Args())
+			}
+		})
+	}
+}
+
+func TestKeysetRejectsMismatchedCursor(t *testing.T) {
+	b := query.NewBuilder(columns)
+
+	_, err := b.Keyset([]page.Order{{Field: "id"}}, &page.Cursor{Values: []any{"1", "2"}})
+	assert.Error(t, err)
+}
+
+func TestPage(t *testing.T) {
+	req := page.Request{
+		Limit: 10,
+		Sort:  []page.Order{{Field: "email", Desc: true}, {Field: "id", Desc: true}},
+		Filter: page.Or{
+			page.Comparison{Field: "age", Op: page.OpGt, Values: []any{int64(1)}},
+			page.Comparison{Field: "age", Op: page.OpEq, Values: []any{nil}},
+		},
+		Cursor: &page.Cursor{Values: []any{"b", "2"}, Backward: true},
+	}
+	b := query.NewBuilder(columns, "static")
+
+	sql, err := b.Page(req)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"AND ((u.age > $2) OR (u.age IS NULL)) AND ((u.email > $3) OR (u.email = $3 AND u.id > $4)) ORDER BY u.email ASC, u.id ASC LIMIT $5",
+		sql,
+	)
+	assert.Equal(t, []any{"static", int64(1), "b", "2", 11}, b.Args())
+}
@@ -0,0 +1,40 @@
+package rbac
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/get_user_roles.sql
+	SQLGetUserRoles string
+	//go:embed sql/get_user_permissions.sql
+	SQLGetUserPermissions string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	return r.names(ctx, SQLGetUserRoles, userId)
+}
+
+func (r *Repository) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	return r.names(ctx, SQLGetUserPermissions, userId)
+}
+
+func (r *Repository) names(ctx context.Context, sql string, userId uuid.UUID) ([]string, error) {
+	rows, err := r.DB.Query(ctx, sql, userId)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	return names, internal.MapError(err)
+}
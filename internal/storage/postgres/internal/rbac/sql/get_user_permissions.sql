@@ -0,0 +1,5 @@
+SELECT DISTINCT p.name
+FROM user_roles ur
+JOIN role_permissions rp ON ur.role_id = rp.role_id
+JOIN permissions p ON rp.permission_id = p.id
+WHERE ur.user_id=$1;
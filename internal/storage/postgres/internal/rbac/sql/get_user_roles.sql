@@ -0,0 +1,4 @@
+SELECT r.name
+FROM user_roles ur
+JOIN roles r ON ur.role_id = r.id
+WHERE ur.user_id=$1;
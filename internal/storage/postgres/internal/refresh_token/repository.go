@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
 	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
 )
@@ -20,33 +22,120 @@ var (
 	SQLUpdateRefreshToken string
 	//go:embed sql/delete_refresh_token.sql
 	SQLDeleteRefreshToken string
+	//go:embed sql/lock_user_sessions.sql
+	SQLLockUserSessions string
+	//go:embed sql/get_active_session_ids.sql
+	SQLGetActiveSessionIds string
+	//go:embed sql/evict_sessions.sql
+	SQLEvictSessions string
 )
 
 type Repository struct {
 	DB *pgxpool.Pool
 }
 
-func (r *Repository) Insert(ctx context.Context, userId uuid.UUID, rTok uuid.UUID, expiresAt time.Time) error {
-	_, err := r.DB.Exec(ctx, SQLNewRefreshToken, userId, rTok, expiresAt)
-	return internal.MapError(err)
+func (r *Repository) Insert(ctx context.Context, rTok auth.RefreshToken, limit auth.SessionLimit) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if limit.MaxPerUser > 0 {
+		if err := enforceSessionLimit(ctx, tx, rTok, limit); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		SQLNewRefreshToken,
+		rTok.UserId,
+		rTok.SessionId,
+		rTok.Value,
+		rTok.ExpiresAt,
+		rTok.SessionStartedAt,
+		rTok.Thumbprint,
+		rTok.AuthTime,
+		rTok.AuthMethod,
+		rTok.OrgId,
+	)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	return internal.MapError(tx.Commit(ctx))
 }
 
-func (r *Repository) Get(ctx context.Context, rTokValue uuid.UUID) (rTok auth.RefreshToken, err error) {
-	err = r.DB.QueryRow(ctx, SQLGetRefreshToken, rTokValue).
+func (r *Repository) Get(ctx context.Context, rTokValue uuid.UUID, rotatedAfter time.Time) (rTok auth.RefreshToken, err error) {
+	err = r.DB.QueryRow(ctx, SQLGetRefreshToken, rTokValue, rotatedAfter).
 		Scan(
 			&rTok.UserId,
+			&rTok.SessionId,
 			&rTok.Value,
 			&rTok.ExpiresAt,
+			&rTok.SessionStartedAt,
+			&rTok.EvictedAt,
+			&rTok.Thumbprint,
+			&rTok.AuthTime,
+			&rTok.AuthMethod,
+			&rTok.OrgId,
 		)
 	return rTok, internal.MapError(err)
 }
 
-func (r *Repository) Update(ctx context.Context, oldRTok uuid.UUID, newRTok uuid.UUID, newRTokExpiresAt time.Time) error {
-	_, err := r.DB.Exec(ctx, SQLUpdateRefreshToken, oldRTok, newRTok, newRTokExpiresAt)
-	return internal.MapError(err)
+func (r *Repository) Update(ctx context.Context, oldRTok uuid.UUID, rTok auth.RefreshToken) error {
+	tag, err := r.DB.Exec(
+		ctx,
+		SQLUpdateRefreshToken,
+		oldRTok,
+		rTok.Value,
+		rTok.ExpiresAt,
+		rTok.Thumbprint,
+		rTok.AuthTime,
+		rTok.AuthMethod,
+		rTok.OrgId,
+	)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
 }
 
 func (r *Repository) Delete(ctx context.Context, rTok uuid.UUID) error {
 	_, err := r.DB.Exec(ctx, SQLDeleteRefreshToken, rTok)
 	return internal.MapError(err)
 }
+
+func enforceSessionLimit(ctx context.Context, tx pgx.Tx, rTok auth.RefreshToken, limit auth.SessionLimit) error {
+	var userId uuid.UUID
+	if err := tx.QueryRow(ctx, SQLLockUserSessions, rTok.UserId).Scan(&userId); err != nil {
+		return internal.MapError(err)
+	}
+
+	rows, err := tx.Query(ctx, SQLGetActiveSessionIds, rTok.UserId, rTok.SessionStartedAt)
+	if err != nil {
+		return internal.MapError(err)
+	}
+	sessionIds, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	excess := len(sessionIds) - limit.MaxPerUser + 1
+	if excess <= 0 {
+		return nil
+	}
+
+	if limit.Policy != auth.SessionLimitEvictOldest {
+		return auth.ErrSessionLimitReached
+	}
+
+	_, err = tx.Exec(ctx, SQLEvictSessions, sessionIds[:excess], rTok.SessionStartedAt)
+	return internal.MapError(err)
+}
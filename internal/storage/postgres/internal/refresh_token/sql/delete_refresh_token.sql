@@ -1,2 +1,2 @@
 DELETE FROM refresh_tokens
-WHERE value=$1;
+WHERE value=$1 OR previous_value=$1;
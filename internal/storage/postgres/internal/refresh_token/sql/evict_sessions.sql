@@ -0,0 +1,3 @@
+UPDATE refresh_tokens
+SET evicted_at = $2
+WHERE session_id = ANY($1);
@@ -0,0 +1,4 @@
+SELECT session_id
+FROM refresh_tokens
+WHERE user_id=$1 AND evicted_at IS NULL AND expires_at > $2
+ORDER BY session_started_at ASC;
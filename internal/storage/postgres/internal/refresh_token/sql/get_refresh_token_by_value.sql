@@ -1,3 +1,3 @@
-SELECT user_id, value, expires_at
+SELECT user_id, session_id, value, expires_at, session_started_at, evicted_at, COALESCE(dpop_jkt, ''), auth_time, auth_method, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
 FROM refresh_tokens
-WHERE value=$1;
+WHERE value=$1 OR (previous_value=$1 AND rotated_at > $2);
@@ -1,2 +1,2 @@
-INSERT INTO refresh_tokens (user_id, value, expires_at)
-VALUES ($1, $2, $3);
+INSERT INTO refresh_tokens (user_id, session_id, value, expires_at, session_started_at, dpop_jkt, auth_time, auth_method, organization_id)
+VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, NULLIF($9, '00000000-0000-0000-0000-000000000000'::uuid));
@@ -1,3 +1,3 @@
 UPDATE refresh_tokens
-SET value = $2, expires_at = $3
-WHERE value = $1;
+SET previous_value = value, rotated_at = NOW(), value = $2, expires_at = $3, dpop_jkt = NULLIF($4, ''), auth_time = $5, auth_method = $6, organization_id = NULLIF($7, '00000000-0000-0000-0000-000000000000'::uuid)
+WHERE value = $1 AND evicted_at IS NULL;
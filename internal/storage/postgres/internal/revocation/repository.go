@@ -0,0 +1,141 @@
+package revocation
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/revocation"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+const (
+	Channel = "access_token_revocations"
+)
+
+var (
+	//go:embed sql/new_revoked_token.sql
+	SQLNewRevokedToken string
+	//go:embed sql/new_revoked_user.sql
+	SQLNewRevokedUser string
+	//go:embed sql/get_active_revoked_tokens.sql
+	SQLGetActiveRevokedTokens string
+	//go:embed sql/get_active_revoked_users.sql
+	SQLGetActiveRevokedUsers string
+	//go:embed sql/delete_expired_revoked_tokens.sql
+	SQLDeleteExpiredRevokedTokens string
+	//go:embed sql/delete_expired_revoked_users.sql
+	SQLDeleteExpiredRevokedUsers string
+	//go:embed sql/notify_revocation.sql
+	SQLNotifyRevocation string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) InsertToken(ctx context.Context, t revocation.RevokedToken) error {
+	return r.insertAndNotify(ctx, revocation.Event{Token: &t}, SQLNewRevokedToken, t.TokenId, t.UserId, t.ExpiresAt)
+}
+
+func (r *Repository) InsertUser(ctx context.Context, u revocation.RevokedUser) error {
+	return r.insertAndNotify(ctx, revocation.Event{User: &u}, SQLNewRevokedUser, u.UserId, u.RevokedBefore)
+}
+
+func (r *Repository) GetActive(ctx context.Context, now time.Time, since time.Time) (tokens []revocation.RevokedToken, users []revocation.RevokedUser, err error) {
+	rows, err := r.DB.Query(ctx, SQLGetActiveRevokedTokens, now)
+	if err != nil {
+		return nil, nil, internal.MapError(err)
+	}
+	tokens, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (t revocation.RevokedToken, err error) {
+		err = row.Scan(&t.TokenId, &t.UserId, &t.ExpiresAt)
+		return t, err
+	})
+	if err != nil {
+		return nil, nil, internal.MapError(err)
+	}
+
+	rows, err = r.DB.Query(ctx, SQLGetActiveRevokedUsers, since)
+	if err != nil {
+		return nil, nil, internal.MapError(err)
+	}
+	users, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (u revocation.RevokedUser, err error) {
+		err = row.Scan(&u.UserId, &u.RevokedBefore)
+		return u, err
+	})
+	if err != nil {
+		return nil, nil, internal.MapError(err)
+	}
+
+	return tokens, users, nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context, now time.Time, since time.Time) error {
+	if _, err := r.DB.Exec(ctx, SQLDeleteExpiredRevokedTokens, now); err != nil {
+		return internal.MapError(err)
+	}
+
+	_, err := r.DB.Exec(ctx, SQLDeleteExpiredRevokedUsers, since)
+	return internal.MapError(err)
+}
+
+func (r *Repository) Listen(ctx context.Context, ready func(), handle func(revocation.Event)) error {
+	conn, err := r.DB.Acquire(ctx)
+	if err != nil {
+		return internal.MapError(err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return internal.MapError(err)
+	}
+
+	ready()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for notification: %w", err)
+		}
+
+		var e revocation.Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			slog.Error(
+				"unmarshaling revocation notification",
+				slog.Any("error", err),
+				slog.String("payload", n.Payload),
+			)
+			continue
+		}
+
+		handle(e)
+	}
+}
+
+func (r *Repository) insertAndNotify(ctx context.Context, e revocation.Event, sql string, args ...any) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling revocation event: %w", err)
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, sql, args...); err != nil {
+		return internal.MapError(err)
+	}
+
+	if _, err = tx.Exec(ctx, SQLNotifyRevocation, string(payload)); err != nil {
+		return internal.MapError(err)
+	}
+
+	return internal.MapError(tx.Commit(ctx))
+}
@@ -0,0 +1,2 @@
+DELETE FROM revoked_access_tokens
+WHERE expires_at <= $1;
@@ -0,0 +1,2 @@
+DELETE FROM revoked_access_token_users
+WHERE revoked_before <= $1;
@@ -0,0 +1,3 @@
+SELECT jti, user_id, expires_at
+FROM revoked_access_tokens
+WHERE expires_at > $1;
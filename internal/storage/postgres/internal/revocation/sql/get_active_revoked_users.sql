@@ -0,0 +1,3 @@
+SELECT user_id, revoked_before
+FROM revoked_access_token_users
+WHERE revoked_before > $1;
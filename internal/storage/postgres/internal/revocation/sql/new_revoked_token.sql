@@ -0,0 +1,3 @@
+INSERT INTO revoked_access_tokens (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING;
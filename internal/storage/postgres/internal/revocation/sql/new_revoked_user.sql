@@ -0,0 +1,4 @@
+INSERT INTO revoked_access_token_users (user_id, revoked_before)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET revoked_before = GREATEST(revoked_access_token_users.revoked_before, EXCLUDED.revoked_before);
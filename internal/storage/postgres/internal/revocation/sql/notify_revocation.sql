@@ -0,0 +1 @@
+SELECT pg_notify('access_token_revocations', $1);
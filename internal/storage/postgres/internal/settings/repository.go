@@ -0,0 +1,48 @@
+package settings
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+)
+
+var (
+	//go:embed sql/get_settings.sql
+	SQLGetSettings string
+	//go:embed sql/update_settings.sql
+	SQLUpdateSettings string
+)
+
+type Repository struct {
+	DB *pgxpool.Pool
+}
+
+func (r *Repository) Get(ctx context.Context, userId uuid.UUID) (s settings.Stored, err error) {
+	err = r.DB.QueryRow(ctx, SQLGetSettings, userId).Scan(&s.Values, &s.DefaultsVersion)
+	return s, internal.MapError(err)
+}
+
+func (r *Repository) Update(
+	ctx context.Context,
+	userId uuid.UUID,
+	set map[string]json.RawMessage,
+	unset []string,
+	defaultsVersion int,
+) (s settings.Stored, err error) {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return s, err
+	}
+	if unset == nil {
+		unset = []string{}
+	}
+
+	err = r.DB.QueryRow(ctx, SQLUpdateSettings, userId, string(data), unset, defaultsVersion).
+		Scan(&s.Values, &s.DefaultsVersion)
+	return s, internal.MapError(err)
+}
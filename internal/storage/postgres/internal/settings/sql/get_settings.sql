@@ -0,0 +1,3 @@
+SELECT data, defaults_version
+FROM user_settings
+WHERE user_id=$1;
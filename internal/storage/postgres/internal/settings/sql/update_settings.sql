@@ -0,0 +1,5 @@
+INSERT INTO user_settings (user_id, data, defaults_version)
+VALUES ($1, $2::jsonb - $3::text[], $4)
+ON CONFLICT (user_id) DO UPDATE
+SET data = (user_settings.data || EXCLUDED.data) - $3::text[], updated_at = NOW()
+RETURNING data, defaults_version;
@@ -3,11 +3,17 @@ package user
 import (
 	"context"
 	_ "embed"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
 	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/query"
 )
 
 var (
@@ -19,8 +25,51 @@ var (
 	SQLNewUser string
 	//go:embed sql/new_linked_account.sql
 	SQLNewLinkedAccount string
+	//go:embed sql/new_user_role.sql
+	SQLNewUserRole string
+	//go:embed sql/search_users.sql
+	SQLSearchUsers string
+	//go:embed sql/get_linked_accounts_by_user.sql
+	SQLGetLinkedAccountsByUser string
+	//go:embed sql/get_sessions_by_user.sql
+	SQLGetSessionsByUser string
+	//go:embed sql/get_user_status.sql
+	SQLGetUserStatus string
+	//go:embed sql/update_user_status.sql
+	SQLUpdateUserStatus string
+	//go:embed sql/delete_user_sessions.sql
+	SQLDeleteUserSessions string
+	//go:embed sql/update_user_profile.sql
+	SQLUpdateUserProfile string
+	//go:embed sql/get_deleted_user_by_provider.sql
+	SQLGetDeletedUserByProvider string
+	//go:embed sql/restore_user.sql
+	SQLRestoreUser string
+	//go:embed sql/purge_deleted_users.sql
+	SQLPurgeDeletedUsers string
+	//go:embed sql/email_exists.sql
+	SQLEmailExists string
+	//go:embed sql/delete_pending_email_changes.sql
+	SQLDeletePendingEmailChanges string
+	//go:embed sql/new_email_change.sql
+	SQLNewEmailChange string
+	//go:embed sql/confirm_email_change.sql
+	SQLConfirmEmailChange string
+	//go:embed sql/update_user_email.sql
+	SQLUpdateUserEmail string
+	//go:embed sql/delete_other_user_sessions.sql
+	SQLDeleteOtherUserSessions string
 )
 
+var userColumns = query.Columns{
+	"id":           "u.id",
+	"email":        "u.email",
+	"status":       "u.status",
+	"display_name": "u.display_name",
+	"created_at":   "u.created_at",
+	"updated_at":   "u.updated_at",
+}
+
 type Repository struct {
 	DB *pgxpool.Pool
 }
@@ -30,6 +79,10 @@ func (r *Repository) Get(ctx context.Context, id uuid.UUID) (u entity.User, err
 		Scan(
 			&u.Id,
 			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
@@ -42,6 +95,10 @@ func (r *Repository) GetByProvider(ctx context.Context, provider, providerID str
 		Scan(
 			&u.Id,
 			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
@@ -66,9 +123,242 @@ func (r *Repository) Insert(ctx context.Context, email, provider, providerUserId
 		return uuid.Nil, internal.MapError(err)
 	}
 
+	_, err = tx.Exec(ctx, SQLNewUserRole, id, rbac.RoleUser)
+	if err != nil {
+		return uuid.Nil, internal.MapError(err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return uuid.Nil, internal.MapError(err)
 	}
 
 	return id, nil
 }
+
+// Search returns up to filter.Page.Limit+1 users in the order of the
+// request, so the caller can tell whether another page follows.
+func (r *Repository) Search(ctx context.Context, filter entity.UserFilter) ([]entity.User, error) {
+	b := query.NewBuilder(
+		userColumns,
+		nullableString(query.EscapeLike(filter.Email)),
+		nullableString(filter.Provider),
+		nullableTime(filter.CreatedAfter),
+		nullableTime(filter.CreatedBefore),
+		nullableString(filter.Status),
+	)
+	clause, err := b.Page(filter.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(ctx, SQLSearchUsers+clause, b.Args()...)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (u entity.User, err error) {
+		err = row.Scan(
+			&u.Id,
+			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+		)
+		return u, err
+	})
+	return users, internal.MapError(err)
+}
+
+func (r *Repository) GetLinkedAccounts(ctx context.Context, userId uuid.UUID) ([]entity.LinkedAccount, error) {
+	rows, err := r.DB.Query(ctx, SQLGetLinkedAccountsByUser, userId)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	accounts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (la entity.LinkedAccount, err error) {
+		err = row.Scan(
+			&la.Provider,
+			&la.ProviderUserId,
+			&la.UpdatedAt,
+		)
+		return la, err
+	})
+	return accounts, internal.MapError(err)
+}
+
+func (r *Repository) GetSessions(ctx context.Context, userId uuid.UUID) ([]entity.Session, error) {
+	rows, err := r.DB.Query(ctx, SQLGetSessionsByUser, userId)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (s entity.Session, err error) {
+		err = row.Scan(
+			&s.Id,
+			&s.StartedAt,
+			&s.ExpiresAt,
+			&s.AuthTime,
+			&s.AuthMethod,
+			&s.EvictedAt,
+			&s.DPoPBound,
+		)
+		return s, err
+	})
+	return sessions, internal.MapError(err)
+}
+
+func (r *Repository) GetStatus(ctx context.Context, id uuid.UUID) (status string, err error) {
+	err = r.DB.QueryRow(ctx, SQLGetUserStatus, id).Scan(&status)
+	return status, internal.MapError(err)
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	tag, err := r.DB.Exec(ctx, SQLUpdateUserStatus, id, from, to)
+	if err != nil {
+		return internal.MapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateProfile(ctx context.Context, id uuid.UUID, patch entity.ProfilePatch) (u entity.User, err error) {
+	err = r.DB.QueryRow(ctx, SQLUpdateUserProfile, id, patch.DisplayName, patch.Locale, patch.Timezone).
+		Scan(
+			&u.Id,
+			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+		)
+
+	return u, internal.MapError(err)
+}
+
+func (r *Repository) GetDeletedByProvider(ctx context.Context, provider, providerUserId string) (id uuid.UUID, deletedAt time.Time, err error) {
+	err = r.DB.QueryRow(ctx, SQLGetDeletedUserByProvider, provider, providerUserId).Scan(&id, &deletedAt)
+	return id, deletedAt, internal.MapError(err)
+}
+
+func (r *Repository) Restore(ctx context.Context, id uuid.UUID, deletedAt time.Time) (u entity.User, err error) {
+	err = r.DB.QueryRow(ctx, SQLRestoreUser, id, deletedAt).
+		Scan(
+			&u.Id,
+			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+		)
+
+	return u, internal.MapError(err)
+}
+
+func (r *Repository) Purge(ctx context.Context, deletedBefore time.Time, limit int) ([]entity.PurgedUser, error) {
+	rows, err := r.DB.Query(ctx, SQLPurgeDeletedUsers, deletedBefore, limit)
+	if err != nil {
+		return nil, internal.MapError(err)
+	}
+
+	purged, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (u entity.PurgedUser, err error) {
+		err = row.Scan(&u.Id, &u.AvatarVersion)
+		return u, err
+	})
+	return purged, internal.MapError(err)
+}
+
+func (r *Repository) EmailExists(ctx context.Context, email string) (exists bool, err error) {
+	err = r.DB.QueryRow(ctx, SQLEmailExists, email).Scan(&exists)
+	return exists, internal.MapError(err)
+}
+
+func (r *Repository) InsertEmailChange(
+	ctx context.Context,
+	userId uuid.UUID,
+	newEmail, tokenHash string,
+	expiresAt time.Time,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, SQLDeletePendingEmailChanges, userId); err != nil {
+		return internal.MapError(err)
+	}
+
+	if _, err := tx.Exec(ctx, SQLNewEmailChange, userId, newEmail, tokenHash, expiresAt); err != nil {
+		return internal.MapError(err)
+	}
+
+	return internal.MapError(tx.Commit(ctx))
+}
+
+func (r *Repository) ConfirmEmailChange(ctx context.Context, userId uuid.UUID, tokenHash string, now time.Time) (u entity.User, err error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return u, internal.MapError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var newEmail string
+	err = tx.QueryRow(ctx, SQLConfirmEmailChange, userId, tokenHash, now).Scan(&newEmail)
+	if err != nil {
+		return u, internal.MapError(err)
+	}
+
+	err = tx.QueryRow(ctx, SQLUpdateUserEmail, userId, newEmail).
+		Scan(
+			&u.Id,
+			&u.Email,
+			&u.Status,
+			&u.DisplayName,
+			&u.Locale,
+			&u.Timezone,
+			&u.CreatedAt,
+			&u.UpdatedAt,
+		)
+	if internal.IsUniqueViolation(err) {
+		return u, fmt.Errorf("%w: email is already in use", core.ErrConflict)
+	} else if err != nil {
+		return u, internal.MapError(err)
+	}
+
+	return u, internal.MapError(tx.Commit(ctx))
+}
+
+func (r *Repository) DeleteSessionsExcept(ctx context.Context, userId, sessionId uuid.UUID) error {
+	_, err := r.DB.Exec(ctx, SQLDeleteOtherUserSessions, userId, sessionId)
+	return internal.MapError(err)
+}
+
+func (r *Repository) DeleteSessions(ctx context.Context, userId uuid.UUID) error {
+	_, err := r.DB.Exec(ctx, SQLDeleteUserSessions, userId)
+	return internal.MapError(err)
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
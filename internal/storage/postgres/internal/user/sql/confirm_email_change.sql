@@ -0,0 +1,4 @@
+UPDATE email_changes
+SET confirmed_at = $3
+WHERE user_id=$1 AND token_hash=$2 AND confirmed_at IS NULL AND expires_at > $3
+RETURNING new_email;
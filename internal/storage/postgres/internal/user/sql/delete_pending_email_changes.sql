@@ -0,0 +1,2 @@
+DELETE FROM email_changes
+WHERE user_id=$1 AND confirmed_at IS NULL;
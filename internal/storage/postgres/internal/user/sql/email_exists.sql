@@ -0,0 +1,5 @@
+SELECT EXISTS (
+  SELECT 1
+  FROM users
+  WHERE email=$1
+);
@@ -0,0 +1,4 @@
+SELECT u.id, u.deleted_at
+FROM linked_accounts la
+JOIN users u ON la.user_id = u.id
+WHERE la.provider=$1 AND la.provider_user_id=$2 AND u.deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+SELECT provider, provider_user_id, updated_at
+FROM linked_accounts
+WHERE user_id=$1
+ORDER BY provider ASC;
@@ -0,0 +1,4 @@
+SELECT session_id, session_started_at, expires_at, auth_time, auth_method, evicted_at, dpop_jkt IS NOT NULL
+FROM refresh_tokens
+WHERE user_id=$1
+ORDER BY session_started_at DESC;
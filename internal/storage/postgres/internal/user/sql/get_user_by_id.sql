@@ -1,3 +1,3 @@
-SELECT id, email, created_at, updated_at
+SELECT id, email, status, display_name, locale, timezone, created_at, updated_at
 FROM users
 WHERE id=$1 AND deleted_at IS NULL;
@@ -1,4 +1,4 @@
-SELECT u.id, u.email, u.created_at, u.updated_at
+SELECT u.id, u.email, u.status, u.display_name, u.locale, u.timezone, u.created_at, u.updated_at
 FROM linked_accounts la
 JOIN users u ON la.user_id = u.id
-WHERE la.provider=$1 AND la.provider_user_id=$2;
+WHERE la.provider=$1 AND la.provider_user_id=$2 AND u.deleted_at IS NULL;
@@ -0,0 +1,3 @@
+SELECT status
+FROM users
+WHERE id=$1;
@@ -0,0 +1,2 @@
+INSERT INTO email_changes (user_id, new_email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4);
@@ -0,0 +1,22 @@
+WITH purged AS (
+  DELETE FROM users
+  WHERE id IN (
+    SELECT id
+    FROM users
+    WHERE status = 'deleted' AND deleted_at <= $1
+    ORDER BY deleted_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING id, COALESCE(avatar_version, '') AS avatar_version
+),
+purged_subscriptions AS (
+  DELETE FROM subscriptions
+  WHERE subject_type = 'user' AND subject_id IN (SELECT id FROM purged)
+),
+purged_usage AS (
+  DELETE FROM usage_counters
+  WHERE subject_type = 'user' AND subject_id IN (SELECT id FROM purged)
+)
+SELECT id, avatar_version
+FROM purged;
@@ -0,0 +1,7 @@
+UPDATE users
+SET status = 'active',
+    status_changed_at = NOW(),
+    updated_at = NOW(),
+    deleted_at = NULL
+WHERE id=$1 AND status = 'deleted' AND deleted_at=$2
+RETURNING id, email, status, display_name, locale, timezone, created_at, updated_at;
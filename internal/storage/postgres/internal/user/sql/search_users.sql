@@ -0,0 +1,11 @@
+SELECT u.id, u.email, u.status, u.display_name, u.locale, u.timezone, u.created_at, u.updated_at
+FROM users u
+WHERE ($1::text IS NULL OR u.email ILIKE '%' || $1 || '%')
+  AND ($2::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM linked_accounts la
+    WHERE la.user_id = u.id AND la.provider = $2
+  ))
+  AND ($3::timestamptz IS NULL OR u.created_at >= $3)
+  AND ($4::timestamptz IS NULL OR u.created_at < $4)
+  AND ($5::text IS NULL OR u.status = $5)
@@ -0,0 +1,4 @@
+UPDATE users
+SET email = $2, updated_at = NOW()
+WHERE id=$1 AND deleted_at IS NULL
+RETURNING id, email, status, display_name, locale, timezone, created_at, updated_at;
@@ -0,0 +1,12 @@
+UPDATE users
+SET display_name = COALESCE($2, display_name),
+    locale = COALESCE($3, locale),
+    timezone = COALESCE($4, timezone),
+    updated_at = CASE
+      WHEN (display_name, locale, timezone) IS DISTINCT FROM
+           (COALESCE($2, display_name), COALESCE($3, locale), COALESCE($4, timezone))
+      THEN NOW()
+      ELSE updated_at
+    END
+WHERE id=$1 AND deleted_at IS NULL
+RETURNING id, email, status, display_name, locale, timezone, created_at, updated_at;
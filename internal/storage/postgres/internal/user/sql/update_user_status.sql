@@ -0,0 +1,6 @@
+UPDATE users
+SET status = $3,
+    status_changed_at = NOW(),
+    updated_at = NOW(),
+    deleted_at = CASE WHEN $3 = 'deleted' THEN NOW() END
+WHERE id=$1 AND status=$2;
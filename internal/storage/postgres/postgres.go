@@ -9,14 +9,31 @@ import (
 )
 
 func New(cfg *config.Config) (*pgxpool.Pool, error) {
-	dbpool, err := pgxpool.New(context.Background(), cfg.DatabaseUrl)
+	poolCfg, err := pgxpool.ParseConfig(cfg.DatabaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing database url: %w", err)
+	}
+
+	if cfg.DatabaseRowLevelSecurity {
+		EnableRowLevelSecurity(poolCfg)
+	}
+
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("new pgxpool: %w", err)
 	}
 
 	if err := dbpool.Ping(context.Background()); err != nil {
+		dbpool.Close()
 		return nil, fmt.Errorf("db ping: %w", err)
 	}
 
+	if cfg.DatabaseRowLevelSecurity {
+		if err := checkRowLevelSecurity(context.Background(), dbpool); err != nil {
+			dbpool.Close()
+			return nil, fmt.Errorf("checking row level security: %w", err)
+		}
+	}
+
 	return dbpool, nil
 }
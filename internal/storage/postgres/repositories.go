@@ -2,7 +2,16 @@ package postgres
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/avatar"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/dpop"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/export"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/flags"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/organization"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/rbac"
 	refreshtoken "github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/refresh_token"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/revocation"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/settings"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres/internal/user"
 )
 
@@ -21,3 +30,75 @@ func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
 		DB: db,
 	}
 }
+
+type RevocationRepository = revocation.Repository
+
+func NewRevocationRepository(db *pgxpool.Pool) *RevocationRepository {
+	return &revocation.Repository{
+		DB: db,
+	}
+}
+
+type RBACRepository = rbac.Repository
+
+func NewRBACRepository(db *pgxpool.Pool) *RBACRepository {
+	return &rbac.Repository{
+		DB: db,
+	}
+}
+
+type OrganizationRepository = organization.Repository
+
+func NewOrganizationRepository(db *pgxpool.Pool) *OrganizationRepository {
+	return &organization.Repository{
+		DB: db,
+	}
+}
+
+type FlagRepository = flags.Repository
+
+func NewFlagRepository(db *pgxpool.Pool) *FlagRepository {
+	return &flags.Repository{
+		DB: db,
+	}
+}
+
+type PlanRepository = plan.Repository
+
+func NewPlanRepository(db *pgxpool.Pool) *PlanRepository {
+	return &plan.Repository{
+		DB: db,
+	}
+}
+
+type DataExportRepository = export.Repository
+
+func NewDataExportRepository(db *pgxpool.Pool) *DataExportRepository {
+	return &export.Repository{
+		DB: db,
+	}
+}
+
+type AvatarRepository = avatar.Repository
+
+func NewAvatarRepository(db *pgxpool.Pool) *AvatarRepository {
+	return &avatar.Repository{
+		DB: db,
+	}
+}
+
+type SettingsRepository = settings.Repository
+
+func NewSettingsRepository(db *pgxpool.Pool) *SettingsRepository {
+	return &settings.Repository{
+		DB: db,
+	}
+}
+
+type DPoPProofRepository = dpop.Repository
+
+func NewDPoPProofRepository(db *pgxpool.Pool) *DPoPProofRepository {
+	return &dpop.Repository{
+		DB: db,
+	}
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+var (
+	//go:embed sql/set_tenant.sql
+	SQLSetTenant string
+	//go:embed sql/role_bypasses_rls.sql
+	SQLRoleBypassesRLS string
+
+	ErrRoleBypassesRLS = errors.New("database role bypasses row level security")
+)
+
+// EnableRowLevelSecurity makes every connection acquired from the pool carry
+// app.user_id and app.org_id from the principal in the acquiring context. The
+// variables are overwritten on each acquire, so a connection never keeps the
+// tenant of a previous request. Contexts without a principal see no tenant
+// rows at all; only contexts marked with policy.AsSystem set app.system and
+// bypass the policies.
+func EnableRowLevelSecurity(cfg *pgxpool.Config) {
+	beforeAcquire := cfg.BeforeAcquire
+	cfg.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if beforeAcquire != nil && !beforeAcquire(ctx, conn) {
+			return false
+		}
+
+		userId, orgId, system := tenant(ctx)
+		if _, err := conn.Exec(ctx, SQLSetTenant, userId, orgId, system); err != nil {
+			slog.Error(
+				"setting tenant on connection",
+				slog.Any("error", err),
+			)
+			return false
+		}
+		return true
+	}
+}
+
+func tenant(ctx context.Context) (userId string, orgId string, system string) {
+	if policy.IsSystem(ctx) {
+		return "", "", "on"
+	}
+
+	p, ok := policy.PrincipalFrom(ctx)
+	if !ok {
+		return "", "", ""
+	}
+
+	if p.UserId != uuid.Nil {
+		userId = p.UserId.String()
+	}
+	if p.OrgId != uuid.Nil {
+		orgId = p.OrgId.String()
+	}
+	return userId, orgId, ""
+}
+
+func checkRowLevelSecurity(ctx context.Context, db *pgxpool.Pool) error {
+	var bypasses bool
+	if err := db.QueryRow(ctx, SQLRoleBypassesRLS).Scan(&bypasses); err != nil {
+		return err
+	}
+
+	if bypasses {
+		return ErrRoleBypassesRLS
+	}
+	return nil
+}
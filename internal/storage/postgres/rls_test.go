@@ -0,0 +1,192 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rlsTestRole = "rls_test"
+)
+
+type tenant struct {
+	userId uuid.UUID
+	orgId  uuid.UUID
+}
+
+// TestRowLevelSecurity needs a migrated database in TEST_DATABASE_URL. When
+// the role in the url bypasses row level security, as superusers do, the
+// test switches to a dedicated role that does not.
+func TestRowLevelSecurity(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	adminCfg, err := pgxpool.ParseConfig(url)
+	require.NoError(t, err)
+	adminCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, "SELECT set_config('app.system', 'on', false)")
+		return err
+	}
+	admin, err := pgxpool.NewWithConfig(ctx, adminCfg)
+	require.NoError(t, err)
+	defer admin.Close()
+
+	a := seedTenant(t, admin)
+	b := seedTenant(t, admin)
+
+	poolCfg, err := pgxpool.ParseConfig(url)
+	require.NoError(t, err)
+	poolCfg.MaxConns = 1
+	if bypassesRLS(t, admin) {
+		useRole(t, admin, poolCfg)
+	}
+	postgres.EnableRowLevelSecurity(poolCfg)
+
+	db, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"should scope organizations", "SELECT id FROM organizations"},
+		{"should scope memberships", "SELECT organization_id FROM memberships"},
+		{"should scope invitations", "SELECT organization_id FROM invitations"},
+		{"should scope subscriptions", "SELECT subject_id FROM subscriptions WHERE subject_type = 'organization'"},
+		{"should scope usage counters", "SELECT subject_id FROM usage_counters WHERE subject_type = 'organization'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, current := range []tenant{a, b} {
+				ctx := policy.WithPrincipal(ctx, policy.Principal{
+					UserId: current.userId,
+					OrgId:  current.orgId,
+				})
+
+				rows, err := db.Query(ctx, tt.query)
+				require.NoError(t, err)
+				orgIds, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+				require.NoError(t, err)
+
+				assert.NotEmpty(t, orgIds)
+				for _, orgId := range orgIds {
+					assert.Equal(t, current.orgId, orgId)
+				}
+			}
+		})
+	}
+
+	t.Run("should see nothing without a principal", func(t *testing.T) {
+		for _, table := range []string{"organizations", "memberships", "invitations", "subscriptions", "usage_counters"} {
+			var count int
+			err := db.QueryRow(ctx, "SELECT COUNT(*) FROM "+table).Scan(&count)
+			require.NoError(t, err)
+			assert.Zero(t, count, table)
+		}
+	})
+
+	t.Run("should see every tenant as system", func(t *testing.T) {
+		ctx := policy.AsSystem(policy.WithPrincipal(ctx, policy.Principal{UserId: a.userId, OrgId: a.orgId}))
+
+		var count int
+		err := db.QueryRow(ctx, "SELECT COUNT(*) FROM invitations WHERE organization_id = ANY($1)", []uuid.UUID{a.orgId, b.orgId}).
+			Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+func seedTenant(t *testing.T, db *pgxpool.Pool) tenant {
+	t.Helper()
+	ctx := context.Background()
+
+	var tnt tenant
+	err := db.QueryRow(ctx, "INSERT INTO users (email) VALUES ($1) RETURNING id", uuid.NewString()+"@rls.test").
+		Scan(&tnt.userId)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec(context.Background(), "DELETE FROM usage_counters WHERE subject_id = $1", tnt.orgId)
+		db.Exec(context.Background(), "DELETE FROM subscriptions WHERE subject_id = $1", tnt.orgId)
+		db.Exec(context.Background(), "DELETE FROM organizations WHERE id = $1", tnt.orgId)
+		db.Exec(context.Background(), "DELETE FROM users WHERE id = $1", tnt.userId)
+	})
+
+	err = db.QueryRow(ctx, "INSERT INTO organizations (name, created_by) VALUES ('rls', $1) RETURNING id", tnt.userId).
+		Scan(&tnt.orgId)
+	require.NoError(t, err)
+
+	_, err = db.Exec(ctx, "INSERT INTO memberships (organization_id, user_id, role) VALUES ($1, $2, 'owner')", tnt.orgId, tnt.userId)
+	require.NoError(t, err)
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO invitations (organization_id, email, role, token_hash, invited_by, expires_at) VALUES ($1, 'invitee@rls.test', 'member', $2, $3, $4)",
+		tnt.orgId,
+		uuid.NewString(),
+		tnt.userId,
+		time.Now().Add(time.Hour),
+	)
+	require.NoError(t, err)
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO subscriptions (subject_type, subject_id, plan_id) SELECT 'organization', $1, id FROM plans WHERE key = 'free'",
+		tnt.orgId,
+	)
+	require.NoError(t, err)
+
+	_, err = db.Exec(
+		ctx,
+		"INSERT INTO usage_counters (subject_type, subject_id, key, period_start) VALUES ('organization', $1, 'invitations', NOW())",
+		tnt.orgId,
+	)
+	require.NoError(t, err)
+
+	return tnt
+}
+
+func bypassesRLS(t *testing.T, db *pgxpool.Pool) bool {
+	t.Helper()
+
+	var bypasses bool
+	err := db.QueryRow(context.Background(), "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").
+		Scan(&bypasses)
+	require.NoError(t, err)
+	return bypasses
+}
+
+func useRole(t *testing.T, admin *pgxpool.Pool, poolCfg *pgxpool.Config) {
+	t.Helper()
+
+	_, err := admin.Exec(context.Background(), `
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '`+rlsTestRole+`') THEN
+				CREATE ROLE `+rlsTestRole+` NOLOGIN NOBYPASSRLS;
+			END IF;
+		END
+		$$;
+		GRANT USAGE ON SCHEMA public TO `+rlsTestRole+`;
+		GRANT SELECT ON ALL TABLES IN SCHEMA public TO `+rlsTestRole+`;
+	`)
+	require.NoError(t, err)
+
+	poolCfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		_, err := conn.Exec(ctx, "SET ROLE "+rlsTestRole)
+		return err
+	}
+}
@@ -0,0 +1,3 @@
+SELECT rolsuper OR rolbypassrls
+FROM pg_roles
+WHERE rolname = current_user;
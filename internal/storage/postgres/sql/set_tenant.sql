@@ -0,0 +1,4 @@
+SELECT
+  set_config('app.user_id', $1, false),
+  set_config('app.org_id', $2, false),
+  set_config('app.system', $3, false);
@@ -12,6 +12,8 @@ import (
 	"github.com/joaovictorsl/go-backend-template/internal/core"
 	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
 	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
 	"github.com/justinas/nosurf"
 	"golang.org/x/oauth2"
 )
@@ -28,14 +30,52 @@ type UserStore interface {
 }
 
 type RefreshTokenStore interface {
-	Insert(ctx context.Context, userId uuid.UUID, rTok uuid.UUID, expiresAt time.Time) error
-	Get(ctx context.Context, rTok uuid.UUID) (RefreshToken, error)
-	Update(ctx context.Context, oldRTok uuid.UUID, newRTok uuid.UUID, newRTokExpiresAt time.Time) error
+	Insert(ctx context.Context, rTok RefreshToken, limit SessionLimit) error
+	// Get also finds the session by the value it had before its last
+	// rotation, if that happened after rotatedAfter.
+	Get(ctx context.Context, rTok uuid.UUID, rotatedAfter time.Time) (RefreshToken, error)
+	Update(ctx context.Context, oldRTok uuid.UUID, rTok RefreshToken) error
 	Delete(ctx context.Context, rTok uuid.UUID) error
 }
 
 type JwtGenerator interface {
-	Generate(userID uuid.UUID) (string, time.Time, error)
+	Generate(identity jwt.Identity) (string, time.Time, error)
+}
+
+type RoleResolver interface {
+	Roles(ctx context.Context, userId uuid.UUID) ([]string, error)
+}
+
+type MembershipResolver interface {
+	GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error)
+}
+
+type InvitationAccepter interface {
+	AcceptInvitation(ctx context.Context, userId uuid.UUID, token string) (entity.Membership, error)
+}
+
+type AccountRestorer interface {
+	RestoreByProvider(ctx context.Context, provider, providerUserId string) (entity.User, error)
+}
+
+type PictureRecorder interface {
+	RecordProviderPicture(ctx context.Context, provider, providerUserId, pictureURL string) error
+}
+
+type AccountStatusChecker interface {
+	Status(ctx context.Context, userId uuid.UUID) (string, error)
+}
+
+type JwtValidator interface {
+	Validate(tokenString string) (*jwt.Claims, error)
+}
+
+type ProofValidator interface {
+	Validate(r *http.Request, accessToken string) (string, error)
+}
+
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, claims *jwt.Claims) error
 }
 
 func HandleOAuth(providers map[string]Provider, oauthStore OAuthStore) http.HandlerFunc {
@@ -48,8 +88,18 @@ func HandleOAuth(providers map[string]Provider, oauthStore OAuthStore) http.Hand
 		}
 
 		state := oauth2.GenerateVerifier()
-		verifier := oauth2.GenerateVerifier()
-		err := oauthStore.Insert(state, verifier)
+		s := oauthState{
+			Verifier:   oauth2.GenerateVerifier(),
+			Reauth:     r.URL.Query().Get("reauth") == "true",
+			Invitation: r.URL.Query().Get("invitation"),
+		}
+
+		rawState, err := s.encode()
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		err = oauthStore.Insert(state, rawState)
 		if err != nil {
 			slog.Error(
 				"inserting state and verifier in oauthStore",
@@ -58,18 +108,24 @@ func HandleOAuth(providers map[string]Provider, oauthStore OAuthStore) http.Hand
 			web.HandleError(err)
 		}
 
-		pUrl := p.AuthCodeURL(state, oauth2.AccessTypeOnline, oauth2.S256ChallengeOption(verifier))
+		opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOnline, oauth2.S256ChallengeOption(s.Verifier)}
+		if s.Reauth {
+			opts = append(opts, oauth2.SetAuthURLParam("max_age", "0"))
+		}
+
+		pUrl := p.AuthCodeURL(state, opts...)
 		http.Redirect(w, r, pUrl, http.StatusTemporaryRedirect)
 	}
 }
 
 func HandleOAuthCallback(
 	providers map[string]Provider,
-	rTokTtl time.Duration,
 	oauthStore OAuthStore,
 	userStore UserStore,
-	refreshTokenStore RefreshTokenStore,
-	jwtGenerator JwtGenerator,
+	accounts AccountRestorer,
+	pictures PictureRecorder,
+	invitations InvitationAccepter,
+	sessions *SessionManager,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		providerKey := r.PathValue("provider")
@@ -82,7 +138,7 @@ func HandleOAuthCallback(
 		query := r.URL.Query()
 		code := query.Get("code")
 		state := query.Get("state")
-		verifier, err := oauthStore.Get(state)
+		rawState, err := oauthStore.Get(state)
 		if errors.Is(err, core.ErrNotFound) {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
@@ -91,7 +147,13 @@ func HandleOAuthCallback(
 		}
 		oauthStore.Remove(state)
 
-		tok, err := p.Exchange(r.Context(), code, oauth2.VerifierOption(verifier))
+		s, err := decodeOAuthState(rawState)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		tok, err := p.Exchange(r.Context(), code, oauth2.VerifierOption(s.Verifier))
 		if err != nil {
 			slog.Error(
 				"exchanging code",
@@ -111,7 +173,15 @@ func HandleOAuthCallback(
 			web.HandleError(err)
 		}
 
+		if s.Reauth {
+			reauthenticate(w, r, providerKey, pu, userStore, sessions)
+			return
+		}
+
 		u, err := userStore.GetByProvider(r.Context(), providerKey, pu.ID)
+		if errors.Is(err, core.ErrNotFound) {
+			u, err = accounts.RestoreByProvider(r.Context(), providerKey, pu.ID)
+		}
 		if errors.Is(err, core.ErrNotFound) {
 			id, err := userStore.Insert(r.Context(), pu.Email, providerKey, pu.ID)
 			if err != nil {
@@ -125,6 +195,7 @@ func HandleOAuthCallback(
 				web.HandleError(err)
 			}
 			u.Id = id
+			u.Status = entity.UserStatusActive
 		} else if err != nil {
 			slog.Error(
 				"getting user by provider on oauth",
@@ -135,54 +206,149 @@ func HandleOAuthCallback(
 			web.HandleError(err)
 		}
 
-		setCookies(w, r, u.Id, rTokTtl, jwtGenerator, refreshTokenStore)
-
-		http.Redirect(w, r, "/home", http.StatusFound)
-	}
-}
-
-func HandleRefresh(rTokTtl time.Duration, refreshTokenStore RefreshTokenStore, jwtGenerator JwtGenerator) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		rTokCookie, err := r.Cookie("rtok")
-		if err != nil {
-			web.HttpErrResponse(w, http.StatusBadRequest, "missing refresh token")
+		if err := AccountStatusError(u.Status); err != nil {
+			web.HttpErrResponse(w, http.StatusForbidden, err.Error())
 			return
 		}
 
-		rTokValue, err := uuid.Parse(rTokCookie.Value)
-		if err != nil {
-			web.HttpErrResponse(w, http.StatusBadRequest, "invalid refresh token")
-			return
+		if err := pictures.RecordProviderPicture(r.Context(), providerKey, pu.ID, pu.Picture); err != nil {
+			slog.Error(
+				"recording provider picture on oauth",
+				slog.Any("error", err),
+				slog.String("user_id", u.Id.String()),
+				slog.String("provider", providerKey),
+			)
 		}
 
-		rTok, err := refreshTokenStore.Get(r.Context(), rTokValue)
-		if err != nil {
-			if errors.Is(err, core.ErrNotFound) {
-				web.HttpErrResponse(w, http.StatusUnauthorized, "invalid refresh token")
-				return
+		redirectTo := "/home"
+		var join func() uuid.UUID
+		if s.Invitation != "" {
+			join = func() uuid.UUID {
+				m, err := invitations.AcceptInvitation(r.Context(), u.Id, s.Invitation)
+				if errors.Is(err, core.ErrNotFound) {
+					redirectTo = "/home?invitation=invalid"
+				} else if errors.Is(err, core.ErrForbidden) {
+					redirectTo = "/home?invitation=email_mismatch"
+				} else if err != nil {
+					slog.Error(
+						"accepting invitation on oauth",
+						slog.Any("error", err),
+						slog.String("user_id", u.Id.String()),
+					)
+					redirectTo = "/home?invitation=failed"
+				}
+				return m.Organization.Id
 			}
+		}
+
+		err = sessions.Start(w, r, u.Id, providerKey, join)
+		if errors.Is(err, ErrSessionLimitReached) {
+			web.HttpErrResponse(w, http.StatusForbidden, err.Error())
+			return
+		} else if err != nil {
 			slog.Error(
-				"retrieving refresh token on refresh",
+				"starting session on oauth",
 				slog.Any("error", err),
-				slog.String("rTok", rTokValue.String()),
+				slog.String("user_id", u.Id.String()),
 			)
 			web.HandleError(err)
 		}
 
-		if time.Now().After(rTok.ExpiresAt) {
-			web.HttpErrResponse(w, http.StatusUnauthorized, "expired refresh token")
+		http.Redirect(w, r, redirectTo, http.StatusFound)
+	}
+}
+
+func reauthenticate(
+	w http.ResponseWriter,
+	r *http.Request,
+	providerKey string,
+	pu *ProviderUser,
+	userStore UserStore,
+	sessions *SessionManager,
+) {
+	u, err := userStore.GetByProvider(r.Context(), providerKey, pu.ID)
+	if errors.Is(err, core.ErrNotFound) {
+		web.HttpErrResponse(w, http.StatusForbidden, ErrReauthenticationMismatch.Error())
+		return
+	} else if err != nil {
+		slog.Error(
+			"getting user by provider on reauthentication",
+			slog.Any("error", err),
+			slog.String("provider", providerKey),
+			slog.String("provider_user_id", pu.ID),
+		)
+		web.HandleError(err)
+	}
+
+	err = sessions.Reauthenticate(w, r, u.Id, providerKey)
+	if errors.Is(err, ErrReauthenticationMismatch) {
+		web.HttpErrResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var authErr Error
+	if errors.As(err, &authErr) {
+		web.HttpErrResponse(w, http.StatusUnauthorized, authErr.Error())
+		return
+	} else if err != nil {
+		slog.Error(
+			"reauthenticating session",
+			slog.Any("error", err),
+			slog.String("user_id", u.Id.String()),
+		)
+		web.HandleError(err)
+	}
+
+	http.Redirect(w, r, "/home", http.StatusFound)
+}
+
+func HandleRefresh(sessions *SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, err := sessions.Refresh(w, r)
+		if errors.Is(err, ErrMissingRefreshToken) || errors.Is(err, ErrMalformedRefreshToken) {
+			web.HttpErrResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		setCookies(w, r, rTok.UserId, rTokTtl, jwtGenerator, refreshTokenStore)
+		var authErr Error
+		if errors.As(err, &authErr) {
+			web.HttpErrResponse(w, http.StatusUnauthorized, authErr.Error())
+			return
+		} else if err != nil {
+			slog.Error(
+				"refreshing session",
+				slog.Any("error", err),
+			)
+			web.HandleError(err)
+		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
-func HandleSignOut(refreshTokenStore RefreshTokenStore) http.HandlerFunc {
+func HandleSignOut(
+	refreshTokenStore RefreshTokenStore,
+	jwtValidator JwtValidator,
+	tokenRevoker TokenRevoker,
+	cookies cookie.Policy,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		rTokCookie, err := r.Cookie("rtok")
+		aTokCookie, err := r.Cookie(cookies.AccessTokenName())
+		if err == nil {
+			claims, err := jwtValidator.Validate(aTokCookie.Value)
+			if err == nil {
+				if err := tokenRevoker.RevokeToken(r.Context(), claims); err != nil {
+					slog.Error(
+						"revoking access token on signout",
+						slog.Any("error", err),
+						slog.String("jti", claims.ID),
+					)
+					web.HandleError(err)
+				}
+			}
+		}
+
+		rTokCookie, err := r.Cookie(cookies.RefreshTokenName())
 		if err == nil {
 			rTokValue, err := uuid.Parse(rTokCookie.Value)
 			if err == nil {
@@ -197,7 +363,7 @@ func HandleSignOut(refreshTokenStore RefreshTokenStore) http.HandlerFunc {
 			}
 		}
 
-		deleteCookies(w)
+		deleteCookies(w, cookies)
 
 		w.WriteHeader(http.StatusOK)
 	}
@@ -206,85 +372,45 @@ func HandleSignOut(refreshTokenStore RefreshTokenStore) http.HandlerFunc {
 func setCookies(
 	w http.ResponseWriter,
 	r *http.Request,
-	userId uuid.UUID,
-	rTokTtl time.Duration,
+	rTok RefreshToken,
+	identity jwt.Identity,
 	jwtGenerator JwtGenerator,
-	refreshTokenStore RefreshTokenStore,
-) {
-	rTok, _ := uuid.NewV7()
-	aTok, aTokExpiresAt, err := jwtGenerator.Generate(userId)
-
-	rTokExpiresAt := time.Now().Add(rTokTtl)
-	err = refreshTokenStore.Insert(r.Context(), userId, rTok, rTokExpiresAt)
+	cookies cookie.Policy,
+) (string, error) {
+	aTok, aTokExpiresAt, err := jwtGenerator.Generate(identity)
 	if err != nil {
-		slog.Error(
-			"inserting refresh token",
-			slog.Any("error", err),
-			slog.String("user_id", userId.String()),
-		)
-		web.HandleError(err)
+		return "", fmt.Errorf("generating access token: %w", err)
 	}
 
-	http.SetCookie(w, configCookie(
-		"rtok",
-		rTok.String(),
-		rTokExpiresAt,
+	http.SetCookie(w, cookies.New(
+		cookies.RefreshTokenName(),
+		rTok.Value.String(),
+		rTok.ExpiresAt,
 		true,
 	))
 
-	http.SetCookie(w, configCookie(
-		"atok",
+	http.SetCookie(w, cookies.New(
+		cookies.AccessTokenName(),
 		aTok,
 		aTokExpiresAt,
 		true,
 	))
 
-	http.SetCookie(w, configCookie(
-		nosurf.CookieName+"_client",
+	http.SetCookie(w, cookies.New(
+		cookies.CSRFClientName(),
 		nosurf.Token(r),
 		aTokExpiresAt,
 		false,
 	))
-}
 
-func configCookie(
-	name string,
-	value string,
-	expiresAt time.Time,
-	httpOnly bool,
-) *http.Cookie {
-	return &http.Cookie{
-		Name:     name,
-		Value:    value,
-		Path:     "/",
-		Expires:  expiresAt,
-		Secure:   false,
-		HttpOnly: httpOnly,
-		SameSite: http.SameSiteLaxMode,
-	}
+	return aTok, nil
 }
 
 func deleteCookies(
 	w http.ResponseWriter,
+	cookies cookie.Policy,
 ) {
-	http.SetCookie(w, &http.Cookie{
-		Name:   "rtok",
-		Value:  "",
-		Path:   "/",
-		MaxAge: -1,
-	})
-
-	http.SetCookie(w, &http.Cookie{
-		Name:   "atok",
-		Value:  "",
-		Path:   "/",
-		MaxAge: -1,
-	})
-
-	http.SetCookie(w, &http.Cookie{
-		Name:   nosurf.CookieName + "_client",
-		Value:  "",
-		Path:   "/",
-		MaxAge: -1,
-	})
+	http.SetCookie(w, cookies.Clear(cookies.RefreshTokenName()))
+	http.SetCookie(w, cookies.Clear(cookies.AccessTokenName()))
+	http.SetCookie(w, cookies.Clear(cookies.CSRFClientName()))
 }
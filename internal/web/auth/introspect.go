@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+)
+
+type RevocationChecker interface {
+	IsRevoked(claims *jwt.Claims) bool
+}
+
+type introspectionResponse struct {
+	Active    bool     `json:"active"`
+	Subject   string   `json:"sub,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	TokenId   string   `json:"jti,omitempty"`
+	SessionId string   `json:"sid,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+
+	Confirmation *jwt.Confirmation `json:"cnf,omitempty"`
+}
+
+func HandleIntrospect(jwtValidator JwtValidator, revocations RevocationChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.PostFormValue("token")
+		if token == "" {
+			writeIntrospection(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+			return
+		}
+
+		claims, err := jwtValidator.Validate(token)
+		if err != nil || revocations.IsRevoked(claims) {
+			writeIntrospection(w, http.StatusOK, introspectionResponse{Active: false})
+			return
+		}
+
+		resp := introspectionResponse{
+			Active:    true,
+			Subject:   claims.Subject,
+			ExpiresAt: claims.ExpiresAt.Unix(),
+			Issuer:    claims.Issuer,
+			Audience:  claims.Audience,
+			TokenId:   claims.ID,
+			SessionId: claims.SessionID.String(),
+			TokenType: "Bearer",
+		}
+		if claims.IssuedAt != nil {
+			resp.IssuedAt = claims.IssuedAt.Unix()
+		}
+		// Sender constrained tokens are only usable with a proof for the
+		// bound key, so resource servers need the thumbprint to verify it.
+		if claims.Thumbprint() != "" {
+			resp.TokenType = "DPoP"
+			resp.Confirmation = claims.Confirmation
+		}
+
+		writeIntrospection(w, http.StatusOK, resp)
+	}
+}
+
+func writeIntrospection(w http.ResponseWriter, status int, v any) {
+	raw, _ := json.Marshal(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(raw)
+}
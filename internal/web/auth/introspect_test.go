@@ -0,0 +1,115 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJwtValidator struct {
+	claims map[string]*jwt.Claims
+}
+
+func (v fakeJwtValidator) Validate(tokenString string) (*jwt.Claims, error) {
+	claims, ok := v.claims[tokenString]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+type fakeRevocations map[string]bool
+
+func (r fakeRevocations) IsRevoked(claims *jwt.Claims) bool {
+	return r[claims.ID]
+}
+
+func TestHandleIntrospect(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	newClaims := func(id string, cnf *jwt.Confirmation) *jwt.Claims {
+		return &jwt.Claims{
+			RegisteredClaims: gojwt.RegisteredClaims{
+				ID:        id,
+				Subject:   "user",
+				Issuer:    "issuer",
+				Audience:  gojwt.ClaimStrings{"audience"},
+				IssuedAt:  gojwt.NewNumericDate(now),
+				ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+			},
+			SessionID:    uuid.New(),
+			Confirmation: cnf,
+		}
+	}
+	validator := fakeJwtValidator{claims: map[string]*jwt.Claims{
+		"bearer":  newClaims("bearer", nil),
+		"dpop":    newClaims("dpop", &jwt.Confirmation{JwkThumbprint: "thumbprint"}),
+		"revoked": newClaims("revoked", nil),
+	}}
+	revocations := fakeRevocations{"revoked": true}
+
+	tests := []struct {
+		name      string
+		token     string
+		status    int
+		active    bool
+		tokenType string
+		jkt       string
+	}{
+		{"should reject request without a token", "", http.StatusBadRequest, false, "", ""},
+		{"should report invalid token as inactive", "garbage", http.StatusOK, false, "", ""},
+		{"should report revoked token as inactive", "revoked", http.StatusOK, false, "", ""},
+		{"should report bearer token as active", "bearer", http.StatusOK, true, "Bearer", ""},
+		{"should report dpop token with its key thumbprint", "dpop", http.StatusOK, true, "DPoP", "thumbprint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{}
+			if tt.token != "" {
+				form.Set("token", tt.token)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/auth/introspect", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			auth.HandleIntrospect(validator, revocations)(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+			var resp struct {
+				Active    bool   `json:"active"`
+				TokenType string `json:"token_type"`
+				Subject   string `json:"sub"`
+				ExpiresAt int64  `json:"exp"`
+				Cnf       *struct {
+					Jkt string `json:"jkt"`
+				} `json:"cnf"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.active, resp.Active)
+			assert.Equal(t, tt.tokenType, resp.TokenType)
+			if tt.active {
+				assert.Equal(t, "user", resp.Subject)
+				assert.Equal(t, now.Add(time.Minute).Unix(), resp.ExpiresAt)
+			}
+			if tt.jkt == "" {
+				assert.Nil(t, resp.Cnf)
+			} else {
+				require.NotNil(t, resp.Cnf)
+				assert.Equal(t, tt.jkt, resp.Cnf.Jkt)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type oauthState struct {
+	Verifier   string `json:"verifier"`
+	Reauth     bool   `json:"reauth,omitempty"`
+	Invitation string `json:"invitation,omitempty"`
+}
+
+func (s oauthState) encode() (string, error) {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("marshaling oauth state: %w", err)
+	}
+	return string(raw), nil
+}
+
+func decodeOAuthState(raw string) (s oauthState, err error) {
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return s, fmt.Errorf("unmarshaling oauth state: %w", err)
+	}
+	return s, nil
+}
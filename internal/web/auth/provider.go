@@ -21,7 +21,7 @@ func GetProviders(cfg *config.Config) map[string]Provider {
 			ClientSecret: cfg.GoogleClientSecret,
 			RedirectURL:  cfg.GoogleClientRedirectUrl,
 			Endpoint:     google.Endpoint,
-			Scopes:       []string{"email"},
+			Scopes:       []string{"email", "profile"},
 		},
 		UserInfoURL:       "https://www.googleapis.com/oauth2/v2/userinfo",
 		Name:              "google",
@@ -32,8 +32,9 @@ func GetProviders(cfg *config.Config) map[string]Provider {
 }
 
 type ProviderUser struct {
-	ID    string
-	Email string
+	ID      string
+	Email   string
+	Picture string
 }
 
 type Provider interface {
@@ -77,14 +78,16 @@ func (p *providerImpl) GetUser(ctx context.Context, tok *oauth2.Token) (*Provide
 
 func parseGoogleUser(raw []byte) (*ProviderUser, error) {
 	var userInfo struct {
-		ID    string `json:"id"`
-		Email string `json:"email"`
+		ID      string `json:"id"`
+		Email   string `json:"email"`
+		Picture string `json:"picture"`
 	}
 	if err := json.Unmarshal(raw, &userInfo); err != nil {
 		return nil, fmt.Errorf("unmarshaling google user: %w %s", err, string(raw))
 	}
 	return &ProviderUser{
-		ID:    userInfo.ID,
-		Email: userInfo.Email,
+		ID:      userInfo.ID,
+		Email:   userInfo.Email,
+		Picture: userInfo.Picture,
 	}, nil
 }
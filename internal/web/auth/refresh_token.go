@@ -7,7 +7,14 @@ import (
 )
 
 type RefreshToken struct {
-	UserId    uuid.UUID
-	Value     uuid.UUID
-	ExpiresAt time.Time
+	UserId           uuid.UUID
+	SessionId        uuid.UUID
+	Value            uuid.UUID
+	ExpiresAt        time.Time
+	SessionStartedAt time.Time
+	EvictedAt        *time.Time
+	Thumbprint       string
+	AuthTime         time.Time
+	AuthMethod       string
+	OrgId            uuid.UUID
 }
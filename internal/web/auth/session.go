@@ -0,0 +1,371 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/dpop"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+)
+
+var (
+	ErrMissingRefreshToken    = Error{"missing refresh token"}
+	ErrMalformedRefreshToken  = Error{"malformed refresh token"}
+	ErrInvalidRefreshToken    = Error{"invalid refresh token"}
+	ErrSessionIdle            = Error{"session expired due to inactivity"}
+	ErrSessionLifetimeExpired = Error{"session reached its maximum lifetime"}
+	ErrSessionEvicted         = Error{"session was ended because the account signed in on too many devices"}
+	ErrSessionLimitReached    = Error{"account is signed in on too many devices"}
+	ErrMissingProof           = Error{"session is bound to a dpop key and requires a dpop proof"}
+	ErrInvalidProof           = Error{"invalid dpop proof"}
+
+	ErrReauthenticationMismatch = Error{"reauthentication must use the account that is signed in"}
+
+	ErrAccountPending   = Error{"account is pending activation"}
+	ErrAccountSuspended = Error{"account is suspended"}
+	ErrAccountDeleted   = Error{"account was deleted"}
+	ErrAccountInactive  = Error{"account is not active"}
+)
+
+const (
+	SessionLimitReject      = "reject"
+	SessionLimitEvictOldest = "evict_oldest"
+)
+
+type Error struct {
+	msg string
+}
+
+func (err Error) Error() string {
+	return err.msg
+}
+
+func AccountStatusError(status string) error {
+	switch status {
+	case entity.UserStatusActive:
+		return nil
+	case entity.UserStatusPending:
+		return ErrAccountPending
+	case entity.UserStatusSuspended:
+		return ErrAccountSuspended
+	case entity.UserStatusDeleted:
+		return ErrAccountDeleted
+	default:
+		return ErrAccountInactive
+	}
+}
+
+type SessionPolicy struct {
+	IdleTimeout      time.Duration
+	AbsoluteLifetime time.Duration
+	// RotationGracePeriod is how long a refresh token keeps working after
+	// being rotated, so concurrent requests renewing with it do not fail.
+	RotationGracePeriod time.Duration
+}
+
+func (p SessionPolicy) ExpiresAt(startedAt time.Time, now time.Time) time.Time {
+	idleExpiresAt := now.Add(p.IdleTimeout)
+	absoluteExpiresAt := startedAt.Add(p.AbsoluteLifetime)
+	if absoluteExpiresAt.Before(idleExpiresAt) {
+		return absoluteExpiresAt
+	}
+	return idleExpiresAt
+}
+
+func (p SessionPolicy) Check(rTok RefreshToken, now time.Time) error {
+	if rTok.EvictedAt != nil {
+		return ErrSessionEvicted
+	}
+	if !now.Before(rTok.SessionStartedAt.Add(p.AbsoluteLifetime)) {
+		return ErrSessionLifetimeExpired
+	}
+	if !now.Before(rTok.ExpiresAt) {
+		return ErrSessionIdle
+	}
+	return nil
+}
+
+type SessionLimit struct {
+	MaxPerUser int
+	Policy     string
+}
+
+type SessionManager struct {
+	policy            SessionPolicy
+	limit             SessionLimit
+	refreshTokenStore RefreshTokenStore
+	jwtGenerator      JwtGenerator
+	proofValidator    ProofValidator
+	roleResolver      RoleResolver
+	memberships       MembershipResolver
+	statusChecker     AccountStatusChecker
+	cookies           cookie.Policy
+}
+
+func NewSessionManager(
+	policy SessionPolicy,
+	limit SessionLimit,
+	refreshTokenStore RefreshTokenStore,
+	jwtGenerator JwtGenerator,
+	proofValidator ProofValidator,
+	roleResolver RoleResolver,
+	memberships MembershipResolver,
+	statusChecker AccountStatusChecker,
+	cookies cookie.Policy,
+) *SessionManager {
+	return &SessionManager{
+		policy:            policy,
+		limit:             limit,
+		refreshTokenStore: refreshTokenStore,
+		jwtGenerator:      jwtGenerator,
+		proofValidator:    proofValidator,
+		roleResolver:      roleResolver,
+		memberships:       memberships,
+		statusChecker:     statusChecker,
+		cookies:           cookies,
+	}
+}
+
+// Start signs the user in. join, when not nil, runs once the session is
+// stored and returns the organization to make active, so work such as
+// accepting an invitation is only done for sessions that could start.
+func (sm *SessionManager) Start(w http.ResponseWriter, r *http.Request, userId uuid.UUID, authMethod string, join func() uuid.UUID) error {
+	now := time.Now()
+	sessionId, _ := uuid.NewV7()
+	rTokValue, _ := uuid.NewV7()
+	rTok := RefreshToken{
+		UserId:           userId,
+		SessionId:        sessionId,
+		Value:            rTokValue,
+		ExpiresAt:        sm.policy.ExpiresAt(now, now),
+		SessionStartedAt: now,
+		AuthTime:         now,
+		AuthMethod:       authMethod,
+	}
+
+	if err := sm.bindProof(r, &rTok); err != nil {
+		return err
+	}
+
+	err := sm.refreshTokenStore.Insert(r.Context(), rTok, sm.limit)
+	if errors.Is(err, ErrSessionLimitReached) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("inserting refresh token: %w", err)
+	}
+
+	if join != nil {
+		if rTok.OrgId = join(); rTok.OrgId != uuid.Nil {
+			_, err = sm.rotate(w, r, rTok)
+			return err
+		}
+	}
+
+	_, err = sm.issue(w, r, rTok)
+	return err
+}
+
+func (sm *SessionManager) Refresh(w http.ResponseWriter, r *http.Request) (string, error) {
+	rTok, err := sm.current(r)
+	if err != nil {
+		return "", err
+	}
+
+	if err := sm.checkProof(r, rTok); err != nil {
+		return "", err
+	}
+
+	// The presented token was rotated by a concurrent request moments ago,
+	// so the client gets the rotated one instead of a second rotation.
+	if presented, _ := sm.presented(r); presented != rTok.Value {
+		return sm.issue(w, r, rTok)
+	}
+
+	return sm.rotate(w, r, rTok)
+}
+
+func (sm *SessionManager) Reauthenticate(w http.ResponseWriter, r *http.Request, userId uuid.UUID, authMethod string) error {
+	rTok, err := sm.current(r)
+	if err != nil {
+		return err
+	}
+
+	if rTok.UserId != userId {
+		return ErrReauthenticationMismatch
+	}
+
+	rTok.AuthTime = time.Now()
+	rTok.AuthMethod = authMethod
+
+	_, err = sm.rotate(w, r, rTok)
+	return err
+}
+
+func (sm *SessionManager) SwitchOrganization(w http.ResponseWriter, r *http.Request, userId uuid.UUID, orgId uuid.UUID) error {
+	rTok, err := sm.current(r)
+	if err != nil {
+		return err
+	}
+
+	if rTok.UserId != userId {
+		return ErrInvalidRefreshToken
+	}
+
+	rTok.OrgId = orgId
+
+	_, err = sm.rotate(w, r, rTok)
+	return err
+}
+
+// Reissue rotates the current session so its access token carries a fresh
+// issued at time, e.g. after the user's older tokens were revoked.
+func (sm *SessionManager) Reissue(w http.ResponseWriter, r *http.Request, userId uuid.UUID) error {
+	rTok, err := sm.current(r)
+	if err != nil {
+		return err
+	}
+
+	if rTok.UserId != userId {
+		return ErrInvalidRefreshToken
+	}
+
+	_, err = sm.rotate(w, r, rTok)
+	return err
+}
+
+func (sm *SessionManager) presented(r *http.Request) (uuid.UUID, error) {
+	rTokCookie, err := r.Cookie(sm.cookies.RefreshTokenName())
+	if err != nil {
+		return uuid.Nil, ErrMissingRefreshToken
+	}
+
+	rTokValue, err := uuid.Parse(rTokCookie.Value)
+	if err != nil {
+		return uuid.Nil, ErrMalformedRefreshToken
+	}
+	return rTokValue, nil
+}
+
+func (sm *SessionManager) current(r *http.Request) (RefreshToken, error) {
+	rTokValue, err := sm.presented(r)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	rotatedAfter := time.Now().Add(-sm.policy.RotationGracePeriod)
+	rTok, err := sm.refreshTokenStore.Get(r.Context(), rTokValue, rotatedAfter)
+	if errors.Is(err, core.ErrNotFound) {
+		return RefreshToken{}, ErrInvalidRefreshToken
+	} else if err != nil {
+		return RefreshToken{}, fmt.Errorf("retrieving refresh token: %w", err)
+	}
+
+	if err := sm.policy.Check(rTok, time.Now()); err != nil {
+		return RefreshToken{}, err
+	}
+
+	status, err := sm.statusChecker.Status(r.Context(), rTok.UserId)
+	if err != nil {
+		return RefreshToken{}, fmt.Errorf("checking account status: %w", err)
+	}
+	if err := AccountStatusError(status); err != nil {
+		return RefreshToken{}, err
+	}
+
+	return rTok, nil
+}
+
+func (sm *SessionManager) rotate(w http.ResponseWriter, r *http.Request, rTok RefreshToken) (string, error) {
+	oldRTokValue := rTok.Value
+	rTok.Value, _ = uuid.NewV7()
+	rTok.ExpiresAt = sm.policy.ExpiresAt(rTok.SessionStartedAt, time.Now())
+
+	err := sm.refreshTokenStore.Update(r.Context(), oldRTokValue, rTok)
+	if errors.Is(err, core.ErrNotFound) {
+		return "", ErrInvalidRefreshToken
+	} else if err != nil {
+		return "", fmt.Errorf("rotating refresh token: %w", err)
+	}
+
+	return sm.issue(w, r, rTok)
+}
+
+func (sm *SessionManager) issue(w http.ResponseWriter, r *http.Request, rTok RefreshToken) (string, error) {
+	roles, err := sm.roleResolver.Roles(r.Context(), rTok.UserId)
+	if err != nil {
+		return "", fmt.Errorf("resolving roles: %w", err)
+	}
+
+	identity := jwt.Identity{
+		UserID:     rTok.UserId,
+		SessionID:  rTok.SessionId,
+		Roles:      roles,
+		Thumbprint: rTok.Thumbprint,
+		AuthTime:   rTok.AuthTime,
+	}
+	if rTok.AuthMethod != "" {
+		identity.AuthMethods = []string{rTok.AuthMethod}
+	}
+
+	if rTok.OrgId != uuid.Nil {
+		// Refreshes carry no principal yet, so the lookup runs as the
+		// session's user to pass row level security.
+		ctx := policy.WithPrincipal(r.Context(), policy.Principal{UserId: rTok.UserId})
+		m, err := sm.memberships.GetMembership(ctx, rTok.OrgId, rTok.UserId)
+		if err == nil {
+			identity.OrgID = rTok.OrgId
+			identity.OrgRole = m.Role
+		} else if !errors.Is(err, core.ErrNotFound) {
+			return "", fmt.Errorf("resolving membership: %w", err)
+		}
+	}
+
+	return setCookies(w, r, rTok, identity, sm.jwtGenerator, sm.cookies)
+}
+
+// bindProof binds a new session to the key of its proof, if any. Sessions
+// are only bound when issued, so a stolen refresh token for an unbound
+// session cannot be pinned to an attacker's key.
+func (sm *SessionManager) bindProof(r *http.Request, rTok *RefreshToken) error {
+	if !dpop.HasProof(r) {
+		return nil
+	}
+
+	thumbprint, err := sm.proofValidator.Validate(r, "")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	rTok.Thumbprint = thumbprint
+	return nil
+}
+
+func (sm *SessionManager) checkProof(r *http.Request, rTok RefreshToken) error {
+	if !dpop.HasProof(r) {
+		if rTok.Thumbprint != "" {
+			return ErrMissingProof
+		}
+		return nil
+	}
+
+	if rTok.Thumbprint == "" {
+		return fmt.Errorf("%w: session is not bound to a key", ErrInvalidProof)
+	}
+
+	thumbprint, err := sm.proofValidator.Validate(r, "")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	if rTok.Thumbprint != thumbprint {
+		return fmt.Errorf("%w: key does not match session", ErrInvalidProof)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	corerevocation "github.com/joaovictorsl/go-backend-template/internal/core/revocation"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+	"github.com/joaovictorsl/go-backend-template/internal/web/revocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRefreshTokens struct {
+	tokens    map[uuid.UUID]auth.RefreshToken
+	insertErr error
+}
+
+func (s *fakeRefreshTokens) Insert(ctx context.Context, rTok auth.RefreshToken, limit auth.SessionLimit) error {
+	if s.insertErr != nil {
+		return s.insertErr
+	}
+	s.tokens[rTok.Value] = rTok
+	return nil
+}
+
+func (s *fakeRefreshTokens) Get(ctx context.Context, rTok uuid.UUID, rotatedAfter time.Time) (auth.RefreshToken, error) {
+	t, ok := s.tokens[rTok]
+	if !ok {
+		return auth.RefreshToken{}, core.ErrNotFound
+	}
+	return t, nil
+}
+
+func (s *fakeRefreshTokens) Update(ctx context.Context, oldRTok uuid.UUID, rTok auth.RefreshToken) error {
+	if _, ok := s.tokens[oldRTok]; !ok {
+		return core.ErrNotFound
+	}
+	delete(s.tokens, oldRTok)
+	s.tokens[rTok.Value] = rTok
+	return nil
+}
+
+func (s *fakeRefreshTokens) Delete(ctx context.Context, rTok uuid.UUID) error {
+	delete(s.tokens, rTok)
+	return nil
+}
+
+type fakeRoles struct{}
+
+func (fakeRoles) Roles(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	return []string{"user"}, nil
+}
+
+type fakeMemberships struct{}
+
+func (fakeMemberships) GetMembership(ctx context.Context, orgId, userId uuid.UUID) (entity.Membership, error) {
+	return entity.Membership{}, core.ErrNotFound
+}
+
+type activeStatus struct{}
+
+func (activeStatus) Status(ctx context.Context, userId uuid.UUID) (string, error) {
+	return entity.UserStatusActive, nil
+}
+
+type noProofs struct{}
+
+func (noProofs) Validate(r *http.Request, accessToken string) (string, error) {
+	return "", nil
+}
+
+type fakeRevocationStore struct{}
+
+func (fakeRevocationStore) InsertToken(ctx context.Context, t corerevocation.RevokedToken) error {
+	return nil
+}
+
+func (fakeRevocationStore) InsertUser(ctx context.Context, u corerevocation.RevokedUser) error {
+	return nil
+}
+
+func (fakeRevocationStore) GetActive(ctx context.Context, now time.Time, since time.Time) ([]corerevocation.RevokedToken, []corerevocation.RevokedUser, error) {
+	return nil, nil, nil
+}
+
+func (fakeRevocationStore) DeleteExpired(ctx context.Context, now time.Time, since time.Time) error {
+	return nil
+}
+
+func (fakeRevocationStore) Listen(ctx context.Context, ready func(), handle func(corerevocation.Event)) error {
+	return nil
+}
+
+func TestSessionPolicyExpiresAt(t *testing.T) {
+	policy := auth.SessionPolicy{
+		IdleTimeout:      time.Hour,
+		AbsoluteLifetime: 24 * time.Hour,
+	}
+	startedAt := time.Now()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			"should return idle expiry when it comes first",
+			startedAt.Add(time.Hour),
+			startedAt.Add(2 * time.Hour),
+		},
+		{
+			"should return absolute expiry when it comes first",
+			startedAt.Add(23*time.Hour + 30*time.Minute),
+			startedAt.Add(24 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.ExpiresAt(startedAt, tt.now))
+		})
+	}
+}
+
+func TestSessionPolicyCheck(t *testing.T) {
+	policy := auth.SessionPolicy{
+		IdleTimeout:      time.Hour,
+		AbsoluteLifetime: 24 * time.Hour,
+	}
+	startedAt := time.Now()
+
+	tests := []struct {
+		name string
+		rTok auth.RefreshToken
+		now  time.Time
+		err  error
+	}{
+		{
+			"should accept session within both limits",
+			auth.RefreshToken{SessionStartedAt: startedAt, ExpiresAt: startedAt.Add(time.Hour)},
+			startedAt.Add(time.Minute),
+			nil,
+		},
+		{
+			"should reject session idle for too long",
+			auth.RefreshToken{SessionStartedAt: startedAt, ExpiresAt: startedAt.Add(time.Hour)},
+			startedAt.Add(2 * time.Hour),
+			auth.ErrSessionIdle,
+		},
+		{
+			"should reject session past its absolute lifetime",
+			auth.RefreshToken{SessionStartedAt: startedAt, ExpiresAt: startedAt.Add(48 * time.Hour)},
+			startedAt.Add(25 * time.Hour),
+			auth.ErrSessionLifetimeExpired,
+		},
+		{
+			"should reject evicted session",
+			auth.RefreshToken{SessionStartedAt: startedAt, ExpiresAt: startedAt.Add(time.Hour), EvictedAt: &startedAt},
+			startedAt.Add(time.Minute),
+			auth.ErrSessionEvicted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, policy.Check(tt.rTok, tt.now), tt.err)
+		})
+	}
+}
+
+func newSessionManager(store auth.RefreshTokenStore, tokens auth.JwtGenerator, cookies cookie.Policy) *auth.SessionManager {
+	return auth.NewSessionManager(
+		auth.SessionPolicy{IdleTimeout: time.Hour, AbsoluteLifetime: 24 * time.Hour},
+		auth.SessionLimit{},
+		store,
+		tokens,
+		noProofs{},
+		fakeRoles{},
+		fakeMemberships{},
+		activeStatus{},
+		cookies,
+	)
+}
+
+func TestStartJoinsOnlyStartedSessions(t *testing.T) {
+	cookies := cookie.NewPolicy(config.CookieConfig{AccessTokenName: "atok", RefreshTokenName: "rtok", CSRFName: "csrf"})
+	tokens, err := jwt.NewTokenManager("0123456789abcdef0123456789abcdef", time.Minute, "issuer", "audience")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		insertErr error
+		joined    bool
+	}{
+		{"should join once the session is stored", nil, true},
+		{"should not join when the session limit is reached", auth.ErrSessionLimitReached, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRefreshTokens{tokens: map[uuid.UUID]auth.RefreshToken{}, insertErr: tt.insertErr}
+			sm := newSessionManager(store, tokens, cookies)
+			orgId := uuid.New()
+			joined := false
+
+			r := httptest.NewRequest(http.MethodGet, "/auth/google/callback", nil)
+			err := sm.Start(httptest.NewRecorder(), r, uuid.New(), "google", func() uuid.UUID {
+				joined = true
+				return orgId
+			})
+
+			assert.Equal(t, tt.joined, joined)
+			if tt.insertErr != nil {
+				assert.ErrorIs(t, err, tt.insertErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, store.tokens, 1)
+			for _, rTok := range store.tokens {
+				assert.Equal(t, orgId, rTok.OrgId)
+			}
+		})
+	}
+}
+
+func TestReissueSurvivesUserRevocation(t *testing.T) {
+	cookies := cookie.NewPolicy(config.CookieConfig{AccessTokenName: "atok", RefreshTokenName: "rtok", CSRFName: "csrf"})
+	tokens, err := jwt.NewTokenManager("0123456789abcdef0123456789abcdef", time.Minute, "issuer", "audience")
+	require.NoError(t, err)
+
+	now := time.Now()
+	userId := uuid.New()
+	rTok := auth.RefreshToken{
+		UserId:           userId,
+		SessionId:        uuid.New(),
+		Value:            uuid.New(),
+		ExpiresAt:        now.Add(time.Hour),
+		SessionStartedAt: now,
+	}
+	sm := newSessionManager(&fakeRefreshTokens{tokens: map[uuid.UUID]auth.RefreshToken{rTok.Value: rTok}}, tokens, cookies)
+	revocations := revocation.New(fakeRevocationStore{}, time.Minute)
+
+	require.NoError(t, revocations.RevokeUser(context.Background(), userId, time.Now().Truncate(time.Second)))
+
+	r := httptest.NewRequest(http.MethodPost, "/users/me/email/confirm", nil)
+	r.AddCookie(&http.Cookie{Name: cookies.RefreshTokenName(), Value: rTok.Value.String()})
+	w := httptest.NewRecorder()
+	require.NoError(t, sm.Reissue(w, r, userId))
+
+	var aTok string
+	for _, c := range w.Result().Cookies() {
+		if c.Name == cookies.AccessTokenName() {
+			aTok = c.Value
+		}
+	}
+	claims, err := tokens.Validate(aTok)
+	require.NoError(t, err)
+	assert.False(t, revocations.IsRevoked(claims))
+}
@@ -0,0 +1,60 @@
+package cookie
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+)
+
+const (
+	csrfClientSuffix = "_client"
+)
+
+type Policy struct {
+	cfg config.CookieConfig
+}
+
+func NewPolicy(cfg config.CookieConfig) Policy {
+	return Policy{cfg: cfg}
+}
+
+func (p Policy) AccessTokenName() string {
+	return p.cfg.Prefix + p.cfg.AccessTokenName
+}
+
+func (p Policy) RefreshTokenName() string {
+	return p.cfg.Prefix + p.cfg.RefreshTokenName
+}
+
+func (p Policy) CSRFName() string {
+	return p.cfg.Prefix + p.cfg.CSRFName
+}
+
+func (p Policy) CSRFClientName() string {
+	return p.CSRFName() + csrfClientSuffix
+}
+
+func (p Policy) Base(name string, httpOnly bool) http.Cookie {
+	return http.Cookie{
+		Name:     name,
+		Path:     "/",
+		Domain:   p.cfg.Domain,
+		Secure:   p.cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: p.cfg.SameSite,
+	}
+}
+
+func (p Policy) New(name string, value string, expiresAt time.Time, httpOnly bool) *http.Cookie {
+	c := p.Base(name, httpOnly)
+	c.Value = value
+	c.Expires = expiresAt
+	return &c
+}
+
+func (p Policy) Clear(name string) *http.Cookie {
+	c := p.Base(name, true)
+	c.MaxAge = -1
+	return &c
+}
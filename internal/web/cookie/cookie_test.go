@@ -0,0 +1,83 @@
+package cookie_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyNames(t *testing.T) {
+	cfg := config.CookieConfig{
+		AccessTokenName:  "atok",
+		RefreshTokenName: "rtok",
+		CSRFName:         "csrf_token",
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		access  string
+		refresh string
+		csrf    string
+	}{
+		{"should use bare names without a prefix", "", "atok", "rtok", "csrf_token"},
+		{"should prepend host prefix", "__Host-", "__Host-atok", "__Host-rtok", "__Host-csrf_token"},
+		{"should prepend secure prefix", "__Secure-", "__Secure-atok", "__Secure-rtok", "__Secure-csrf_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Prefix = tt.prefix
+			p := cookie.NewPolicy(cfg)
+
+			assert.Equal(t, tt.access, p.AccessTokenName())
+			assert.Equal(t, tt.refresh, p.RefreshTokenName())
+			assert.Equal(t, tt.csrf, p.CSRFName())
+			assert.Equal(t, tt.csrf+"_client", p.CSRFClientName())
+		})
+	}
+}
+
+func TestPolicyAttributes(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		secure   bool
+		domain   string
+		sameSite http.SameSite
+	}{
+		{"should carry lax same site on insecure cookies", false, "", http.SameSiteLaxMode},
+		{"should carry strict same site on secure cookies", true, "", http.SameSiteStrictMode},
+		{"should carry none same site on secure cookies", true, "example.com", http.SameSiteNoneMode},
+		{"should carry default same site", true, "", http.SameSiteDefaultMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := cookie.NewPolicy(config.CookieConfig{
+				Secure:   tt.secure,
+				Domain:   tt.domain,
+				SameSite: tt.sameSite,
+			})
+
+			c := p.New("atok", "value", expiresAt, true)
+			assert.Equal(t, "atok", c.Name)
+			assert.Equal(t, "value", c.Value)
+			assert.Equal(t, "/", c.Path)
+			assert.Equal(t, tt.domain, c.Domain)
+			assert.Equal(t, tt.secure, c.Secure)
+			assert.Equal(t, tt.sameSite, c.SameSite)
+			assert.True(t, c.HttpOnly)
+			assert.Equal(t, expiresAt, c.Expires)
+
+			cleared := p.Clear("atok")
+			assert.Equal(t, -1, cleared.MaxAge)
+			assert.Empty(t, cleared.Value)
+			assert.Equal(t, tt.secure, cleared.Secure)
+			assert.Equal(t, tt.sameSite, cleared.SameSite)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package dpop
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	HeaderName = "DPoP"
+	Scheme     = "DPoP"
+	proofType  = "dpop+jwt"
+)
+
+var (
+	ErrMissingProof = errors.New("missing dpop proof")
+	ErrInvalidProof = errors.New("invalid dpop proof")
+	ErrReplayedJti  = errors.New("dpop proof jti was already used")
+
+	validMethods = []string{"ES256", "ES384", "ES512", "RS256", "PS256", "EdDSA"}
+)
+
+type proofClaims struct {
+	jwt.RegisteredClaims
+	HTTPMethod      string `json:"htm"`
+	HTTPURI         string `json:"htu"`
+	AccessTokenHash string `json:"ath,omitempty"`
+}
+
+type Validator struct {
+	maxAge         time.Duration
+	trustedProxies []netip.Prefix
+	replays        ReplayStore
+}
+
+// NewValidator returns a Validator accepting proofs up to maxAge old. The
+// X-Forwarded-Proto header is only honored on requests coming from one of
+// trustedProxies.
+func NewValidator(maxAge time.Duration, trustedProxies []netip.Prefix, replays ReplayStore) *Validator {
+	return &Validator{
+		maxAge:         maxAge,
+		trustedProxies: trustedProxies,
+		replays:        replays,
+	}
+}
+
+func HasProof(r *http.Request) bool {
+	return r.Header.Get(HeaderName) != ""
+}
+
+// Validate checks the DPoP proof sent with r and returns the thumbprint of
+// the key that signed it. When accessToken is not empty the proof must also
+// carry its hash in the ath claim.
+func (v *Validator) Validate(r *http.Request, accessToken string) (string, error) {
+	values := r.Header.Values(HeaderName)
+	if len(values) == 0 {
+		return "", ErrMissingProof
+	}
+	if len(values) > 1 {
+		return "", fmt.Errorf("%w: multiple proofs", ErrInvalidProof)
+	}
+
+	var thumbprint string
+	claims := &proofClaims{}
+	_, err := jwt.ParseWithClaims(
+		values[0],
+		claims,
+		func(token *jwt.Token) (any, error) {
+			if typ, _ := token.Header["typ"].(string); typ != proofType {
+				return nil, fmt.Errorf("unexpected typ %q", typ)
+			}
+
+			k, err := parseJWK(token.Header["jwk"])
+			if err != nil {
+				return nil, err
+			}
+
+			thumbprint, err = k.Thumbprint()
+			if err != nil {
+				return nil, err
+			}
+
+			return k.PublicKey()
+		},
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	if claims.ID == "" || claims.IssuedAt == nil {
+		return "", fmt.Errorf("%w: missing jti or iat", ErrInvalidProof)
+	}
+
+	now := time.Now()
+	if age := now.Sub(claims.IssuedAt.Time); age > v.maxAge || age < -v.maxAge {
+		return "", fmt.Errorf("%w: iat outside of accepted window", ErrInvalidProof)
+	}
+
+	if claims.HTTPMethod != r.Method {
+		return "", fmt.Errorf("%w: htm does not match request method", ErrInvalidProof)
+	}
+
+	if !matchesRequestURI(claims.HTTPURI, r, v.scheme(r)) {
+		return "", fmt.Errorf("%w: htu does not match request uri", ErrInvalidProof)
+	}
+
+	if accessToken != "" {
+		expected := AccessTokenHash(accessToken)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(claims.AccessTokenHash)) != 1 {
+			return "", fmt.Errorf("%w: ath does not match access token", ErrInvalidProof)
+		}
+	}
+
+	// A proof is accepted for maxAge on either side of now, so its jti has
+	// to be remembered for twice as long.
+	fresh, err := v.replays.Remember(r.Context(), thumbprint+":"+claims.ID, now.Add(2*v.maxAge))
+	if err != nil {
+		return "", fmt.Errorf("checking dpop proof replay: %w", err)
+	}
+	if !fresh {
+		return "", ErrReplayedJti
+	}
+
+	return thumbprint, nil
+}
+
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Run deletes expired jtis from the replay store until ctx is done.
+func (v *Validator) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.maxAge)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.replays.DeleteExpired(ctx, time.Now()); err != nil {
+				slog.Error(
+					"deleting expired dpop proofs",
+					slog.Any("error", err),
+				)
+			}
+		}
+	}
+}
+
+func (v *Validator) scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+
+	proto := r.Header.Get("X-Forwarded-Proto")
+	if proto == "" || !v.fromTrustedProxy(r) {
+		return "http"
+	}
+	return proto
+}
+
+func (v *Validator) fromTrustedProxy(r *http.Request) bool {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+
+	addr := addrPort.Addr().Unmap()
+	for _, p := range v.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRequestURI(htu string, r *http.Request, scheme string) bool {
+	u, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return strings.EqualFold(u.Scheme, scheme) &&
+		strings.EqualFold(u.Host, r.Host) &&
+		path == r.URL.Path
+}
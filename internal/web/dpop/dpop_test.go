@@ -0,0 +1,227 @@
+package dpop_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/web/dpop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type proof struct {
+	typ string
+	htm string
+	htu string
+	iat time.Time
+	jti string
+	ath string
+}
+
+func newProof(t *testing.T, key *ecdsa.PrivateKey, p proof) string {
+	t.Helper()
+
+	claims := gojwt.MapClaims{
+		"htm": p.htm,
+		"htu": p.htu,
+		"iat": p.iat.Unix(),
+		"jti": p.jti,
+	}
+	if p.ath != "" {
+		claims["ath"] = p.ath
+	}
+
+	token := gojwt.NewWithClaims(gojwt.SigningMethodES256, claims)
+	token.Header["typ"] = p.typ
+	token.Header["jwk"] = map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func validProof() proof {
+	return proof{
+		typ: "dpop+jwt",
+		htm: "GET",
+		htu: "http://example.com/users/me",
+		iat: time.Now(),
+		jti: uuid.NewString(),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	accessToken := "access-token"
+
+	tests := []struct {
+		name        string
+		proof       func() proof
+		accessToken string
+		wantErr     bool
+	}{
+		{
+			"should accept valid proof",
+			validProof,
+			"",
+			false,
+		},
+		{
+			"should accept proof carrying the access token hash",
+			func() proof {
+				p := validProof()
+				p.ath = dpop.AccessTokenHash(accessToken)
+				return p
+			},
+			accessToken,
+			false,
+		},
+		{
+			"should reject proof without the access token hash",
+			validProof,
+			accessToken,
+			true,
+		},
+		{
+			"should reject proof with wrong typ",
+			func() proof {
+				p := validProof()
+				p.typ = "JWT"
+				return p
+			},
+			"",
+			true,
+		},
+		{
+			"should reject proof for another method",
+			func() proof {
+				p := validProof()
+				p.htm = "POST"
+				return p
+			},
+			"",
+			true,
+		},
+		{
+			"should reject proof for another uri",
+			func() proof {
+				p := validProof()
+				p.htu = "http://example.com/auth/refresh"
+				return p
+			},
+			"",
+			true,
+		},
+		{
+			"should reject stale proof",
+			func() proof {
+				p := validProof()
+				p.iat = time.Now().Add(-time.Hour)
+				return p
+			},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := dpop.NewValidator(time.Minute, nil, dpop.NewMemoryReplayStore())
+
+			r := httptest.NewRequest("GET", "http://example.com/users/me?x=1", nil)
+			r.Header.Set(dpop.HeaderName, newProof(t, key, tt.proof()))
+
+			thumbprint, err := v.Validate(r, tt.accessToken)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotEmpty(t, thumbprint)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsReplayedProof(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := dpop.NewValidator(time.Minute, nil, dpop.NewMemoryReplayStore())
+	r := httptest.NewRequest("GET", "http://example.com/users/me", nil)
+	r.Header.Set(dpop.HeaderName, newProof(t, key, validProof()))
+
+	_, err = v.Validate(r, "")
+	require.NoError(t, err)
+
+	_, err = v.Validate(r, "")
+	assert.ErrorIs(t, err, dpop.ErrReplayedJti)
+}
+
+func TestValidateReturnsStableThumbprint(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	v := dpop.NewValidator(time.Minute, nil, dpop.NewMemoryReplayStore())
+
+	var thumbprints []string
+	for range 2 {
+		r := httptest.NewRequest("GET", "http://example.com/users/me", nil)
+		r.Header.Set(dpop.HeaderName, newProof(t, key, validProof()))
+
+		thumbprint, err := v.Validate(r, "")
+		require.NoError(t, err)
+		thumbprints = append(thumbprints, thumbprint)
+	}
+
+	assert.Equal(t, thumbprints[0], thumbprints[1])
+}
+
+func TestValidateForwardedProto(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	proxy := netip.MustParsePrefix("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantErr    bool
+	}{
+		{"should honor header from trusted proxy", "10.1.2.3:4567", false},
+		{"should ignore header from untrusted client", "192.0.2.1:4567", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := dpop.NewValidator(time.Minute, []netip.Prefix{proxy}, dpop.NewMemoryReplayStore())
+
+			p := validProof()
+			p.htu = "https://example.com/users/me"
+
+			r := httptest.NewRequest("GET", "http://example.com/users/me", nil)
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("X-Forwarded-Proto", "https")
+			r.Header.Set(dpop.HeaderName, newProof(t, key, p))
+
+			_, err := v.Validate(r, "")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package dpop
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	minRSAKeyBits = 2048
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported jwk")
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	D   string `json:"d,omitempty"`
+}
+
+func parseJWK(raw any) (jwk, error) {
+	var k jwk
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return k, fmt.Errorf("marshaling jwk: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &k); err != nil {
+		return k, fmt.Errorf("unmarshaling jwk: %w", err)
+	}
+
+	if k.D != "" {
+		return k, fmt.Errorf("%w: jwk contains a private key", ErrUnsupportedKey)
+	}
+
+	return k, nil
+}
+
+func (k jwk) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "EC":
+		return k.ecdsaPublicKey()
+	case "RSA":
+		return k.rsaPublicKey()
+	case "OKP":
+		return k.ed25519PublicKey()
+	default:
+		return nil, fmt.Errorf("%w: kty %q", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// Thumbprint computes the RFC 7638 JWK thumbprint, which hashes only the
+// required members of the key in lexicographic order.
+func (k jwk) Thumbprint() (string, error) {
+	var members map[string]string
+	switch k.Kty {
+	case "EC":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X, "y": k.Y}
+	case "RSA":
+		members = map[string]string{"e": k.E, "kty": k.Kty, "n": k.N}
+	case "OKP":
+		members = map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X}
+	default:
+		return "", fmt.Errorf("%w: kty %q", ErrUnsupportedKey, k.Kty)
+	}
+
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("marshaling jwk thumbprint members: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func (k jwk) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var (
+		curve  elliptic.Curve
+		ecurve ecdh.Curve
+	)
+	switch k.Crv {
+	case "P-256":
+		curve, ecurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecurve = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("%w: crv %q", ErrUnsupportedKey, k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk y: %w", err)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, fmt.Errorf("%w: invalid coordinate size", ErrUnsupportedKey)
+	}
+
+	uncompressed := append([]byte{4}, append(x, y...)...)
+	if _, err := ecurve.NewPublicKey(uncompressed); err != nil {
+		return nil, fmt.Errorf("%w: point is not on curve", ErrUnsupportedKey)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk e: %w", err)
+	}
+
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	if pub.N.BitLen() < minRSAKeyBits || pub.E < 3 || pub.E%2 == 0 {
+		return nil, fmt.Errorf("%w: weak rsa key", ErrUnsupportedKey)
+	}
+
+	return pub, nil
+}
+
+func (k jwk) ed25519PublicKey() (ed25519.PublicKey, error) {
+	if k.Crv != "Ed25519" {
+		return nil, fmt.Errorf("%w: crv %q", ErrUnsupportedKey, k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwk x: %w", err)
+	}
+	if len(x) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: invalid ed25519 key size", ErrUnsupportedKey)
+	}
+
+	return ed25519.PublicKey(x), nil
+}
@@ -0,0 +1,54 @@
+package dpop
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplayStore remembers the jti of every accepted proof until it expires.
+// It has to be shared by every instance serving requests, otherwise a proof
+// replayed against another instance goes unnoticed.
+type ReplayStore interface {
+	// Remember reports whether key was not seen before, storing it until
+	// expiresAt.
+	Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// MemoryReplayStore keeps jtis in process. It is only safe for a single
+// instance deployment and for tests.
+type MemoryReplayStore struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{
+		seen: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryReplayStore) Remember(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if seenUntil, ok := s.seen[key]; ok && time.Now().Before(seenUntil) {
+		return false, nil
+	}
+
+	s.seen[key] = expiresAt
+	return true, nil
+}
+
+func (s *MemoryReplayStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, expiresAt := range s.seen {
+		if now.After(expiresAt) {
+			delete(s.seen, k)
+		}
+	}
+	return nil
+}
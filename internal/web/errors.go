@@ -1,17 +1,22 @@
 package web
 
 import (
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
 )
 
 func HttpErrResponse(w http.ResponseWriter, status int, msg string) {
+	raw, _ := json.Marshal(struct {
+		Message string `json:"message"`
+	}{msg})
 	w.WriteHeader(status)
-	fmt.Fprintf(w, `{"message": "%s"}`, msg)
+	w.Write(raw)
 }
 
 func HandleError(err error) {
@@ -21,6 +26,15 @@ func HandleError(err error) {
 type HttpError struct {
 	status  int
 	message string
+	details any
+}
+
+type quotaDetails struct {
+	Key       string     `json:"key"`
+	Quota     int64      `json:"quota"`
+	Used      int64      `json:"used"`
+	Requested int64      `json:"requested"`
+	ResetsAt  *time.Time `json:"resets_at"`
 }
 
 func HttpErrorFrom(err error) HttpError {
@@ -29,17 +43,42 @@ func HttpErrorFrom(err error) HttpError {
 		message string = "Something went wrong on our side"
 	)
 
+	var quotaErr plan.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return HttpError{
+			http.StatusTooManyRequests,
+			quotaErr.Error(),
+			quotaDetails{
+				Key:       quotaErr.Key,
+				Quota:     quotaErr.Quota,
+				Used:      quotaErr.Used,
+				Requested: quotaErr.Requested,
+				ResetsAt:  quotaErr.ResetsAt,
+			},
+		}
+	}
+
 	var coreErr core.Error
 	if !errors.As(err, &coreErr) {
 		return HttpError{
 			status,
 			message,
+			nil,
 		}
 	}
 
 	if errors.Is(coreErr, core.ErrNotFound) {
 		status = http.StatusNotFound
 		message = "We couldn't find what you were looking for"
+	} else if errors.Is(coreErr, core.ErrForbidden) {
+		status = http.StatusForbidden
+		message = "You are not allowed to do this"
+	} else if errors.Is(coreErr, core.ErrInvalid) {
+		status = http.StatusBadRequest
+		message = err.Error()
+	} else if errors.Is(coreErr, core.ErrConflict) {
+		status = http.StatusConflict
+		message = err.Error()
 	} else {
 		slog.Error(
 			"matching core error",
@@ -50,6 +89,7 @@ func HttpErrorFrom(err error) HttpError {
 	return HttpError{
 		status,
 		message,
+		nil,
 	}
 }
 
@@ -62,6 +102,8 @@ func (err HttpError) Status() int {
 }
 
 func (err HttpError) MarshalJSON() ([]byte, error) {
-	jsonErr := fmt.Sprintf(`{"message": "%s"}`, err.message)
-	return []byte(jsonErr), nil
+	return json.Marshal(struct {
+		Message string `json:"message"`
+		Details any    `json:"details,omitempty"`
+	}{err.message, err.details})
 }
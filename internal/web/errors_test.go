@@ -0,0 +1,62 @@
+package web_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpErrorMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{
+			"should escape quotes in messages",
+			fmt.Errorf(`%w: unknown status %q`, core.ErrInvalid, "on\\hold"),
+			http.StatusBadRequest,
+			`invalid input: unknown status "on\\hold"`,
+		},
+		{
+			"should hide internal errors",
+			fmt.Errorf(`db said "no"`),
+			http.StatusInternalServerError,
+			"Something went wrong on our side",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpErr := web.HttpErrorFrom(tt.err)
+
+			raw, err := json.Marshal(httpErr)
+			require.NoError(t, err)
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(raw, &body))
+			assert.Equal(t, tt.status, httpErr.Status())
+			assert.Equal(t, map[string]any{"message": tt.message}, body)
+		})
+	}
+}
+
+func TestHttpErrResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	web.HttpErrResponse(w, http.StatusBadRequest, `invalid sort field "na\me"`)
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `invalid sort field "na\me"`, body.Message)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	user "github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/pagination"
+)
+
+type adminUserResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newAdminUserResponse(u entity.User) adminUserResponse {
+	return adminUserResponse{
+		Id:        u.Id,
+		Email:     u.Email,
+		Status:    u.Status,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+type adminLinkedAccountResponse struct {
+	Provider       string    `json:"provider"`
+	ProviderUserId string    `json:"provider_user_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type adminSessionResponse struct {
+	Id         uuid.UUID  `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AuthTime   time.Time  `json:"auth_time"`
+	AuthMethod string     `json:"auth_method"`
+	EvictedAt  *time.Time `json:"evicted_at,omitempty"`
+	DPoPBound  bool       `json:"dpop_bound"`
+}
+
+type adminUserDetailsResponse struct {
+	adminUserResponse
+	LinkedAccounts []adminLinkedAccountResponse `json:"linked_accounts"`
+	Sessions       []adminSessionResponse       `json:"sessions"`
+}
+
+// HandleAdminListUsers lists users a page at a time. Besides the dedicated
+// query parameters it accepts the sort, filter and cursor parameters of the
+// pagination package over user.UserListFields.
+func HandleAdminListUsers(u *user.AdminUseCase, cursors *pagination.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := entity.UserFilter{
+			Email:    query.Get("email"),
+			Provider: query.Get("provider"),
+			Status:   query.Get("status"),
+		}
+
+		var err error
+		if filter.CreatedAfter, err = parseTimeParam(query.Get("created_after")); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "created_after must be an RFC 3339 timestamp")
+			return
+		}
+		if filter.CreatedBefore, err = parseTimeParam(query.Get("created_before")); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "created_before must be an RFC 3339 timestamp")
+			return
+		}
+		filter.Page, err = cursors.ParseRequest(query, pagination.Options{
+			Fields:       user.UserListFields,
+			DefaultSort:  user.DefaultUserSort,
+			DefaultLimit: user.DefaultSearchLimit,
+			MaxLimit:     user.MaxSearchLimit,
+			Params:       []string{"email", "provider", "status", "created_after", "created_before"},
+		})
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		users, err := u.Search(r.Context(), filter)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, pagination.NewEnvelope(cursors, filter.Page, users, newAdminUserResponse))
+	}
+}
+
+func HandleAdminGetUser(u *user.AdminUseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid user id")
+			return
+		}
+
+		details, err := u.Get(r.Context(), id)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		res := adminUserDetailsResponse{
+			adminUserResponse: newAdminUserResponse(details.User),
+			LinkedAccounts:    make([]adminLinkedAccountResponse, 0, len(details.LinkedAccounts)),
+			Sessions:          make([]adminSessionResponse, 0, len(details.Sessions)),
+		}
+		for _, la := range details.LinkedAccounts {
+			res.LinkedAccounts = append(res.LinkedAccounts, adminLinkedAccountResponse{
+				Provider:       la.Provider,
+				ProviderUserId: la.ProviderUserId,
+				UpdatedAt:      la.UpdatedAt,
+			})
+		}
+		for _, s := range details.Sessions {
+			res.Sessions = append(res.Sessions, adminSessionResponse{
+				Id:         s.Id,
+				StartedAt:  s.StartedAt,
+				ExpiresAt:  s.ExpiresAt,
+				AuthTime:   s.AuthTime,
+				AuthMethod: s.AuthMethod,
+				EvictedAt:  s.EvictedAt,
+				DPoPBound:  s.DPoPBound,
+			})
+		}
+
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleAdminSuspendUser(u *user.AdminUseCase) http.HandlerFunc {
+	return handleAdminUserAction(u.Suspend)
+}
+
+func HandleAdminActivateUser(u *user.AdminUseCase) http.HandlerFunc {
+	return handleAdminUserAction(u.Activate)
+}
+
+func HandleAdminSignOutUser(u *user.AdminUseCase) http.HandlerFunc {
+	return handleAdminUserAction(u.SignOut)
+}
+
+func handleAdminUserAction(action func(ctx context.Context, id uuid.UUID) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid user id")
+			return
+		}
+
+		if err := action(r.Context(), id); err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/avatar"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+const (
+	avatarFormField = "avatar"
+	// multipartOverhead leaves room for boundaries and part headers on top
+	// of the file itself.
+	multipartOverhead = 64 << 10
+)
+
+var (
+	avatarContentTypes = map[string]bool{
+		"image/jpeg": true,
+		"image/png":  true,
+		"image/gif":  true,
+	}
+)
+
+type avatarResponse struct {
+	Url   string `json:"url"`
+	Sizes []int  `json:"sizes"`
+}
+
+// HandleUploadAvatar accepts a multipart/form-data body with the image in
+// the avatar field. Both the declared and the sniffed content type must be
+// an accepted image type.
+func HandleUploadAvatar(s *avatar.Service, maxBytes int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusUnsupportedMediaType, "content type must be multipart/form-data")
+			return
+		}
+
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				if writeBodyTooLarge(w, err, maxBytes) {
+					return
+				}
+				web.HttpErrResponse(w, http.StatusBadRequest, "missing "+avatarFormField+" field")
+				return
+			}
+			if part.FormName() != avatarFormField {
+				continue
+			}
+
+			mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if !avatarContentTypes[mediaType] {
+				web.HttpErrResponse(w, http.StatusUnsupportedMediaType, "avatar must be a jpeg, png or gif image")
+				return
+			}
+
+			data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+			if err != nil {
+				if writeBodyTooLarge(w, err, maxBytes) {
+					return
+				}
+				web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+				return
+			}
+			if int64(len(data)) > maxBytes {
+				writeBodyTooLarge(w, &http.MaxBytesError{Limit: maxBytes}, maxBytes)
+				return
+			}
+			if http.DetectContentType(data) != mediaType {
+				web.HttpErrResponse(w, http.StatusUnsupportedMediaType, "avatar content does not match its content type")
+				return
+			}
+
+			userId := request.GetUserId(r)
+			version, err := s.Upload(r.Context(), userId, data)
+			if err != nil {
+				web.HandleError(err)
+			}
+
+			web.JsonResponse(w, http.StatusOK, avatarResponse{
+				Url:   fmt.Sprintf("/users/%s/avatar?v=%s", userId, version),
+				Sizes: avatar.Sizes,
+			})
+			return
+		}
+	}
+}
+
+func HandleDeleteAvatar(s *avatar.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.Remove(r.Context(), request.GetUserId(r)); err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetAvatar serves the uploaded avatar closest to the requested size,
+// falling back to a redirect to the identity provider's picture. Requests
+// that name the current version through the v parameter may be cached for
+// good, since a new upload always gets a new version.
+func HandleGetAvatar(s *avatar.Service, cacheMaxAge time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId := request.GetUserId(r)
+		if id := r.PathValue("id"); id != "me" {
+			var err error
+			userId, err = uuid.Parse(id)
+			if err != nil {
+				web.HttpErrResponse(w, http.StatusBadRequest, "invalid user id")
+				return
+			}
+		}
+
+		query := r.URL.Query()
+		size := avatar.DefaultSize
+		if raw := query.Get("size"); raw != "" {
+			requested, err := strconv.Atoi(raw)
+			if err != nil {
+				web.HttpErrResponse(w, http.StatusBadRequest, avatar.ErrInvalidSize.Error())
+				return
+			}
+			size, err = avatar.FitSize(requested)
+			if err != nil {
+				web.HandleError(err)
+			}
+		}
+
+		a, err := s.Get(r.Context(), userId)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		maxAge := int(cacheMaxAge.Seconds())
+		switch {
+		case a.Version != "":
+			img, err := s.Open(r.Context(), userId, a.Version, size)
+			if err != nil {
+				web.HandleError(err)
+			}
+			defer img.Close()
+
+			if query.Get("v") == a.Version {
+				w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+			} else {
+				w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+			}
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("ETag", fmt.Sprintf(`"%s-%d"`, a.Version, size))
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			http.ServeContent(w, r, "", time.Time{}, img)
+		case a.PictureURL != "":
+			w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+			http.Redirect(w, r, a.PictureURL, http.StatusFound)
+		default:
+			web.HttpErrResponse(w, http.StatusNotFound, "user has no avatar")
+		}
+	}
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, err error, maxBytes int64) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	web.HttpErrResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("avatar must be at most %d bytes", maxBytes))
+	return true
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/core/export"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type exportResponse struct {
+	Id                uuid.UUID  `json:"id"`
+	Status            string     `json:"status"`
+	Error             string     `json:"error,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	CompletedAt       *time.Time `json:"completed_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	DownloadUrl       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+func newExportResponse(e entity.DataExport) exportResponse {
+	return exportResponse{
+		Id:          e.Id,
+		Status:      e.Status,
+		Error:       e.Error,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+func HandleRequestExport(s *export.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		e, err := s.Request(r.Context(), request.GetUserId(r))
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("/users/me/exports/%s", e.Id))
+		web.JsonResponse(w, http.StatusAccepted, newExportResponse(e))
+	}
+}
+
+func HandleGetExport(s *export.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid export id")
+			return
+		}
+
+		e, err := s.Get(r.Context(), request.GetUserId(r), id)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		res := newExportResponse(e)
+		if e.Status == entity.ExportStatusCompleted {
+			url, expiresAt, err := s.DownloadURL(e)
+			if err != nil {
+				web.HandleError(err)
+			}
+			res.DownloadUrl = url
+			res.DownloadExpiresAt = &expiresAt
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+// HandleDownloadExport serves the archive behind a signed link. The link is
+// the credential, so this route does not require a session.
+func HandleDownloadExport(s *export.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid export id")
+			return
+		}
+
+		query := r.URL.Query()
+		expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusForbidden, export.ErrInvalidSignature.Error())
+			return
+		}
+
+		archive, err := s.Open(r.Context(), id, expires, query.Get("signature"))
+		if err != nil {
+			web.HandleError(err)
+		}
+		defer archive.Close()
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, id))
+		w.Header().Set("Cache-Control", "private, no-store")
+		http.ServeContent(w, r, "", time.Time{}, archive)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/flags"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+)
+
+type flagRequest struct {
+	Description   string      `json:"description"`
+	Enabled       bool        `json:"enabled"`
+	Percentage    int         `json:"percentage"`
+	Environments  []string    `json:"environments"`
+	Users         []uuid.UUID `json:"users"`
+	Organizations []uuid.UUID `json:"organizations"`
+}
+
+type flagResponse struct {
+	Key           string      `json:"key"`
+	Description   string      `json:"description"`
+	Enabled       bool        `json:"enabled"`
+	Percentage    int         `json:"percentage"`
+	Environments  []string    `json:"environments"`
+	Users         []uuid.UUID `json:"users"`
+	Organizations []uuid.UUID `json:"organizations"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+func newFlagResponse(f flags.Flag) flagResponse {
+	rules := f.Rules.NonNil()
+	return flagResponse{
+		Key:           f.Key,
+		Description:   f.Description,
+		Enabled:       f.Enabled,
+		Percentage:    rules.Percentage,
+		Environments:  rules.Environments,
+		Users:         rules.Users,
+		Organizations: rules.Organizations,
+		UpdatedAt:     f.UpdatedAt,
+	}
+}
+
+func HandleGetMyFlags(s *flags.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		evaluated, err := s.EvaluateAll(r.Context(), flags.SubjectFrom(r.Context()))
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		web.JsonResponse(w, http.StatusOK, map[string]any{"flags": evaluated})
+	}
+}
+
+func HandleAdminListFlags(s *flags.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		all, err := s.List(r.Context())
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		res := make([]flagResponse, 0, len(all))
+		for _, f := range all {
+			res = append(res, newFlagResponse(f))
+		}
+
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleAdminPutFlag(s *flags.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body flagRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		f, err := s.Save(r.Context(), flags.Flag{
+			Key:         r.PathValue("key"),
+			Description: body.Description,
+			Enabled:     body.Enabled,
+			Rules: flags.Rules{
+				Percentage:    body.Percentage,
+				Environments:  body.Environments,
+				Users:         body.Users,
+				Organizations: body.Organizations,
+			},
+		})
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newFlagResponse(f))
+	}
+}
+
+func HandleAdminDeleteFlag(s *flags.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := s.Delete(r.Context(), r.PathValue("key")); err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	org "github.com/joaovictorsl/go-backend-template/internal/core/organization/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type invitationResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newInvitationResponse(inv entity.Invitation, now time.Time) invitationResponse {
+	return invitationResponse{
+		Id:        inv.Id,
+		Email:     inv.Email,
+		Role:      inv.Role,
+		Status:    inv.Status(now),
+		ExpiresAt: inv.ExpiresAt,
+		CreatedAt: inv.CreatedAt,
+	}
+}
+
+type invitationPreviewResponse struct {
+	Organization struct {
+		Id   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"organization"`
+	Email      string            `json:"email"`
+	Role       string            `json:"role"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	SignInUrls map[string]string `json:"sign_in_urls"`
+}
+
+func HandleCreateInvitation(u *org.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid organization id")
+			return
+		}
+
+		var body struct {
+			Email string `json:"email"`
+			Role  string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		inv, err := u.Invite(r.Context(), request.GetUserId(r), orgId, body.Email, body.Role)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusCreated, newInvitationResponse(inv, time.Now()))
+	}
+}
+
+func HandleListInvitations(u *org.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid organization id")
+			return
+		}
+
+		invitations, err := u.ListInvitations(r.Context(), orgId)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		now := time.Now()
+		res := make([]invitationResponse, 0, len(invitations))
+		for _, inv := range invitations {
+			res = append(res, newInvitationResponse(inv, now))
+		}
+
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleRevokeInvitation(u *org.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid organization id")
+			return
+		}
+
+		invitationId, err := uuid.Parse(r.PathValue("invitationId"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid invitation id")
+			return
+		}
+
+		if err := u.RevokeInvitation(r.Context(), orgId, invitationId); err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandlePreviewInvitation(u *org.UseCase, providers []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			web.HttpErrResponse(w, http.StatusBadRequest, "missing invitation token")
+			return
+		}
+
+		inv, err := u.GetPendingInvitation(r.Context(), token)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		res := invitationPreviewResponse{
+			Email:      inv.Email,
+			Role:       inv.Role,
+			ExpiresAt:  inv.ExpiresAt,
+			SignInUrls: make(map[string]string, len(providers)),
+		}
+		res.Organization.Id = inv.Organization.Id
+		res.Organization.Name = inv.Organization.Name
+		for _, p := range providers {
+			res.SignInUrls[p] = fmt.Sprintf("/oauth/%s?invitation=%s", p, url.QueryEscape(token))
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleAcceptInvitation(u *org.UseCase, switcher OrganizationSwitcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			web.HttpErrResponse(w, http.StatusBadRequest, "missing invitation token")
+			return
+		}
+
+		userId := request.GetUserId(r)
+		m, err := u.AcceptInvitation(r.Context(), userId, token)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		err = switcher.SwitchOrganization(w, r, userId, m.Organization.Id)
+		var authErr auth.Error
+		if errors.As(err, &authErr) {
+			web.HttpErrResponse(w, http.StatusUnauthorized, authErr.Error())
+			return
+		} else if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newOrganizationResponse(m, m.Organization.Id))
+	}
+}
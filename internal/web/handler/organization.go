@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	org "github.com/joaovictorsl/go-backend-template/internal/core/organization/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type OrganizationSwitcher interface {
+	SwitchOrganization(w http.ResponseWriter, r *http.Request, userId uuid.UUID, orgId uuid.UUID) error
+}
+
+type organizationResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newOrganizationResponse(m entity.Membership, activeOrgId uuid.UUID) organizationResponse {
+	return organizationResponse{
+		Id:        m.Organization.Id,
+		Name:      m.Organization.Name,
+		Role:      m.Role,
+		Active:    m.Organization.Id == activeOrgId,
+		CreatedAt: m.Organization.CreatedAt,
+	}
+}
+
+func HandleCreateOrganization(u *org.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		m, err := u.Create(r.Context(), request.GetUserId(r), body.Name)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusCreated, newOrganizationResponse(m, request.GetOrgId(r)))
+	}
+}
+
+func HandleListOrganizations(u *org.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		memberships, err := u.List(r.Context(), request.GetUserId(r))
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		activeOrgId := request.GetOrgId(r)
+		res := make([]organizationResponse, 0, len(memberships))
+		for _, m := range memberships {
+			res = append(res, newOrganizationResponse(m, activeOrgId))
+		}
+
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleSwitchOrganization(u *org.UseCase, switcher OrganizationSwitcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgId, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid organization id")
+			return
+		}
+
+		userId := request.GetUserId(r)
+		m, err := u.Switch(r.Context(), userId, orgId)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		err = switcher.SwitchOrganization(w, r, userId, orgId)
+		var authErr auth.Error
+		if errors.As(err, &authErr) {
+			web.HttpErrResponse(w, http.StatusUnauthorized, authErr.Error())
+			return
+		} else if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newOrganizationResponse(m, orgId))
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+)
+
+type usageResponse struct {
+	Key      string     `json:"key"`
+	Period   string     `json:"period"`
+	Quota    int64      `json:"quota"`
+	Used     int64      `json:"used"`
+	ResetsAt *time.Time `json:"resets_at"`
+}
+
+type planResponse struct {
+	Key         string          `json:"key"`
+	Name        string          `json:"name"`
+	SubjectType string          `json:"subject_type"`
+	SubjectId   uuid.UUID       `json:"subject_id"`
+	Usage       []usageResponse `json:"usage"`
+}
+
+type setPlanRequest struct {
+	Plan string `json:"plan"`
+}
+
+func HandleGetUsage(s *plan.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subject := plan.SubjectFrom(r.Context())
+		p, usage, err := s.Usage(r.Context(), subject)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		res := planResponse{
+			Key:         p.Key,
+			Name:        p.Name,
+			SubjectType: subject.Type,
+			SubjectId:   subject.Id,
+			Usage:       make([]usageResponse, 0, len(usage)),
+		}
+		for _, u := range usage {
+			res.Usage = append(res.Usage, usageResponse{
+				Key:      u.Key,
+				Period:   u.Period,
+				Quota:    u.Quota,
+				Used:     u.Used,
+				ResetsAt: u.ResetsAt,
+			})
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		web.JsonResponse(w, http.StatusOK, res)
+	}
+}
+
+func HandleAdminSetUserPlan(s *plan.Service) http.HandlerFunc {
+	return handleAdminSetPlan(s, plan.SubjectUser)
+}
+
+func HandleAdminSetOrganizationPlan(s *plan.Service) http.HandlerFunc {
+	return handleAdminSetPlan(s, plan.SubjectOrganization)
+}
+
+func handleAdminSetPlan(s *plan.Service, subjectType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "invalid subject id")
+			return
+		}
+
+		var body setPlanRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		// Admins manage plans of subjects outside of their own tenant.
+		ctx := policy.AsSystem(r.Context())
+		err = s.SetPlan(ctx, plan.Subject{Type: subjectType, Id: id}, body.Plan)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type settingsResponse struct {
+	DefaultsVersion int            `json:"defaults_version"`
+	Values          map[string]any `json:"values"`
+}
+
+func newSettingsResponse(s settings.Settings) settingsResponse {
+	return settingsResponse{
+		DefaultsVersion: s.DefaultsVersion,
+		Values:          s.Values,
+	}
+}
+
+func HandleGetSettings(s *settings.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := s.Get(r.Context(), request.GetUserId(r))
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		w.Header().Set("Cache-Control", "private, no-cache")
+		web.JsonResponse(w, http.StatusOK, newSettingsResponse(res))
+	}
+}
+
+// HandleUpdateSettings takes an object with the settings to change. Settings
+// left out keep their value and settings set to null return to their
+// default.
+func HandleUpdateSettings(s *settings.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body == nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		res, err := s.Update(r.Context(), request.GetUserId(r), body)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newSettingsResponse(res))
+	}
+}
@@ -2,23 +2,177 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"mime"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
 	user "github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
 	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
 	"github.com/joaovictorsl/go-backend-template/internal/web/request"
 )
 
+const (
+	mergePatchContentType = "application/merge-patch+json"
+)
+
+type SessionReissuer interface {
+	Reissue(w http.ResponseWriter, r *http.Request, userId uuid.UUID) error
+}
+
+type userResponse struct {
+	Id          uuid.UUID `json:"id"`
+	Email       string    `json:"email"`
+	Status      string    `json:"status"`
+	DisplayName string    `json:"display_name"`
+	Locale      string    `json:"locale"`
+	Timezone    string    `json:"timezone"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newUserResponse(u entity.User) userResponse {
+	return userResponse{
+		Id:          u.Id,
+		Email:       u.Email,
+		Status:      u.Status,
+		DisplayName: u.DisplayName,
+		Locale:      u.Locale,
+		Timezone:    u.Timezone,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}
+}
+
 func HandleGetUser(u *user.UseCase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userId := request.GetUserId(r)
 
-		u, err := u.Get(r.Context(), userId)
+		usr, err := u.Get(r.Context(), userId)
 		if err != nil {
 			web.HandleError(err)
 		}
 
-		raw, _ := json.Marshal(u)
-		w.Write(raw)
+		web.JsonResponse(w, http.StatusOK, newUserResponse(usr))
+	}
+}
+
+// HandleUpdateUser applies an RFC 7396 JSON Merge Patch to the editable
+// profile fields. Members set to null are cleared and absent members are
+// left untouched.
+func HandleUpdateUser(u *user.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != mergePatchContentType && mediaType != "application/json" {
+			w.Header().Set("Accept-Patch", mergePatchContentType)
+			web.HttpErrResponse(w, http.StatusUnsupportedMediaType, "content type must be "+mergePatchContentType)
+			return
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body == nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		var patch entity.ProfilePatch
+		for key, raw := range body {
+			var field **string
+			switch key {
+			case "display_name":
+				field = &patch.DisplayName
+			case "locale":
+				field = &patch.Locale
+			case "timezone":
+				field = &patch.Timezone
+			default:
+				web.HttpErrResponse(w, http.StatusBadRequest, key+" cannot be changed")
+				return
+			}
+
+			var value *string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				web.HttpErrResponse(w, http.StatusBadRequest, key+" must be a string or null")
+				return
+			}
+			if value == nil {
+				value = new(string)
+			}
+			*field = value
+		}
+
+		usr, err := u.UpdateProfile(r.Context(), request.GetUserId(r), patch)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newUserResponse(usr))
+	}
+}
+
+// HandleDeleteUser soft deletes the signed in account and ends all of its
+// sessions. Signing in again during the grace period restores it.
+func HandleDeleteUser(u *user.UseCase, cookies cookie.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := u.Delete(r.Context(), request.GetUserId(r)); err != nil {
+			web.HandleError(err)
+		}
+
+		http.SetCookie(w, cookies.Clear(cookies.RefreshTokenName()))
+		http.SetCookie(w, cookies.Clear(cookies.AccessTokenName()))
+		http.SetCookie(w, cookies.Clear(cookies.CSRFClientName()))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func HandleRequestEmailChange(u *user.UseCase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Email string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		if err := u.RequestEmailChange(r.Context(), request.GetUserId(r), body.Email); err != nil {
+			web.HandleError(err)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// HandleConfirmEmailChange swaps the email and signs out every other session.
+// The current session is reissued so it survives the revocation. When it
+// cannot be, the change still stands and the user has to sign in again.
+func HandleConfirmEmailChange(u *user.UseCase, reissuer SessionReissuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Token == "" {
+			web.HttpErrResponse(w, http.StatusBadRequest, "malformed request body")
+			return
+		}
+
+		userId := request.GetUserId(r)
+		usr, err := u.ConfirmEmailChange(r.Context(), userId, request.GetSessionId(r), body.Token)
+		if err != nil {
+			web.HandleError(err)
+		}
+
+		err = reissuer.Reissue(w, r, userId)
+		var authErr auth.Error
+		if err != nil && !errors.As(err, &authErr) {
+			web.HandleError(err)
+		}
+
+		web.JsonResponse(w, http.StatusOK, newUserResponse(usr))
 	}
 }
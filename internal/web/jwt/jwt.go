@@ -14,38 +14,103 @@ const (
 )
 
 var (
-	ErrInvalidToken   = errors.New("token is invalid or expired")
-	ErrTokenExpired   = errors.New("token has expired")
-	ErrSecretTooShort = fmt.Errorf("invalid secret size: must be at least %d characters", MinSecretSize)
+	ErrInvalidToken    = errors.New("token is invalid or expired")
+	ErrTokenExpired    = errors.New("token has expired")
+	ErrSecretTooShort  = fmt.Errorf("invalid secret size: must be at least %d characters", MinSecretSize)
+	ErrMissingIssuer   = errors.New("issuer must not be empty")
+	ErrMissingAudience = errors.New("audience must not be empty")
 )
 
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID uuid.UUID `json:"user_id"`
+	UserID       uuid.UUID        `json:"user_id"`
+	SessionID    uuid.UUID        `json:"sid"`
+	Roles        []string         `json:"roles,omitempty"`
+	Confirmation *Confirmation    `json:"cnf,omitempty"`
+	AuthTime     *jwt.NumericDate `json:"auth_time,omitempty"`
+	AuthMethods  []string         `json:"amr,omitempty"`
+	OrgID        uuid.UUID        `json:"org_id,omitzero"`
+	OrgRole      string           `json:"org_role,omitempty"`
+}
+
+type Confirmation struct {
+	JwkThumbprint string `json:"jkt"`
+}
+
+func (c *Claims) Thumbprint() string {
+	if c.Confirmation == nil {
+		return ""
+	}
+	return c.Confirmation.JwkThumbprint
+}
+
+type Identity struct {
+	UserID      uuid.UUID
+	SessionID   uuid.UUID
+	Roles       []string
+	Thumbprint  string
+	AuthTime    time.Time
+	AuthMethods []string
+	OrgID       uuid.UUID
+	OrgRole     string
 }
 
 type TokenManager struct {
 	hmacSecret []byte
 	ttl        time.Duration
+	issuer     string
+	audience   string
 }
 
-func NewTokenManager(secret string, ttl time.Duration) (*TokenManager, error) {
+func NewTokenManager(secret string, ttl time.Duration, issuer string, audience string) (*TokenManager, error) {
 	if uint(len(secret)) < MinSecretSize {
 		return nil, ErrSecretTooShort
 	}
-	return &TokenManager{hmacSecret: []byte(secret), ttl: ttl}, nil
+	if issuer == "" {
+		return nil, ErrMissingIssuer
+	}
+	if audience == "" {
+		return nil, ErrMissingAudience
+	}
+	return &TokenManager{
+		hmacSecret: []byte(secret),
+		ttl:        ttl,
+		issuer:     issuer,
+		audience:   audience,
+	}, nil
 }
 
-func (tm *TokenManager) Generate(userID uuid.UUID) (string, time.Time, error) {
+func (tm *TokenManager) Generate(identity Identity) (string, time.Time, error) {
+	jti, err := uuid.NewV7()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("generating jti: %w", err)
+	}
+
 	now := time.Now()
 	expiresAt := now.Add(tm.ttl)
 	claims := Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti.String(),
+			Issuer:    tm.issuer,
+			Audience:  jwt.ClaimStrings{tm.audience},
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
-			Subject:   userID.String(),
+			Subject:   identity.UserID.String(),
 		},
-		UserID: userID,
+		UserID:    identity.UserID,
+		SessionID: identity.SessionID,
+		Roles:     identity.Roles,
+	}
+	if !identity.AuthTime.IsZero() {
+		claims.AuthTime = jwt.NewNumericDate(identity.AuthTime)
+		claims.AuthMethods = identity.AuthMethods
+	}
+	if identity.OrgID != uuid.Nil {
+		claims.OrgID = identity.OrgID
+		claims.OrgRole = identity.OrgRole
+	}
+	if identity.Thumbprint != "" {
+		claims.Confirmation = &Confirmation{JwkThumbprint: identity.Thumbprint}
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -56,13 +121,14 @@ func (tm *TokenManager) Generate(userID uuid.UUID) (string, time.Time, error) {
 }
 
 func (tm *TokenManager) Validate(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		return tm.hmacSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&Claims{},
+		tm.keyFunc,
+		jwt.WithIssuer(tm.issuer),
+		jwt.WithAudience(tm.audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -79,3 +145,11 @@ func (tm *TokenManager) Validate(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+func (tm *TokenManager) keyFunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return tm.hmacSecret, nil
+}
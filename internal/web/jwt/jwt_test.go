@@ -18,35 +18,64 @@ func randomString(length uint) string {
 	return string(s)
 }
 
+const (
+	defaultIssuer   = "issuer"
+	defaultAudience = "audience"
+)
+
 func TestNewTokenManager(t *testing.T) {
 	tests := []struct {
-		name   string
-		secret string
-		ttl    time.Duration
-		err    error
+		name     string
+		secret   string
+		ttl      time.Duration
+		issuer   string
+		audience string
+		err      error
 	}{
 		{
 			"should return error when secret size is too short",
 			randomString(jwt.MinSecretSize - 1),
 			time.Second,
+			defaultIssuer,
+			defaultAudience,
 			jwt.ErrSecretTooShort,
 		},
+		{
+			"should return error when issuer is empty",
+			randomString(jwt.MinSecretSize),
+			time.Second,
+			"",
+			defaultAudience,
+			jwt.ErrMissingIssuer,
+		},
+		{
+			"should return error when audience is empty",
+			randomString(jwt.MinSecretSize),
+			time.Second,
+			defaultIssuer,
+			"",
+			jwt.ErrMissingAudience,
+		},
 		{
 			"should return jwt manager when secret size is minimum",
 			randomString(jwt.MinSecretSize),
 			time.Second,
+			defaultIssuer,
+			defaultAudience,
 			nil,
 		},
 		{
 			"should return jwt manager when secret size is greater than minimum",
 			randomString(jwt.MinSecretSize + 1),
 			time.Second,
+			defaultIssuer,
+			defaultAudience,
 			nil,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := jwt.NewTokenManager(tt.secret, tt.ttl)
+			got, gotErr := jwt.NewTokenManager(tt.secret, tt.ttl, tt.issuer, tt.audience)
 			assert.ErrorIs(t, tt.err, gotErr)
 			if tt.err != nil {
 				assert.Nil(t, got)
@@ -60,6 +89,9 @@ func TestNewTokenManager(t *testing.T) {
 func TestGenerateAndValidateTokenManager(t *testing.T) {
 	defaultSecret := randomString(jwt.MinSecretSize)
 	defaultUserId, _ := uuid.NewV7()
+	defaultSessionId, _ := uuid.NewV7()
+	defaultOrgId, _ := uuid.NewV7()
+	defaultAuthTime := time.Now().Add(-time.Minute)
 	gojwt.TimePrecision = time.Nanosecond
 
 	tests := []struct {
@@ -80,10 +112,18 @@ func TestGenerateAndValidateTokenManager(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tm, err := jwt.NewTokenManager(defaultSecret, tt.ttl)
+			tm, err := jwt.NewTokenManager(defaultSecret, tt.ttl, defaultIssuer, defaultAudience)
 			require.NoError(t, err)
 
-			tokStr, expiresAt, err := tm.Generate(defaultUserId)
+			tokStr, expiresAt, err := tm.Generate(jwt.Identity{
+				UserID:      defaultUserId,
+				SessionID:   defaultSessionId,
+				Roles:       []string{"admin"},
+				AuthTime:    defaultAuthTime,
+				AuthMethods: []string{"google"},
+				OrgID:       defaultOrgId,
+				OrgRole:     "owner",
+			})
 			require.NoError(t, err)
 
 			time.Sleep(time.Millisecond)
@@ -96,9 +136,54 @@ func TestGenerateAndValidateTokenManager(t *testing.T) {
 				require.NoError(t, err)
 				assert.Equal(t, defaultUserId, claims.UserID)
 				assert.Equal(t, defaultUserId.String(), claims.Subject)
+				assert.NotEmpty(t, claims.ID)
+				assert.Equal(t, defaultIssuer, claims.Issuer)
+				assert.Equal(t, gojwt.ClaimStrings{defaultAudience}, claims.Audience)
+				assert.Equal(t, defaultSessionId, claims.SessionID)
+				assert.Equal(t, []string{"admin"}, claims.Roles)
+				assert.WithinDuration(t, defaultAuthTime, claims.AuthTime.Time, time.Second)
+				assert.Equal(t, []string{"google"}, claims.AuthMethods)
+				assert.Equal(t, defaultOrgId, claims.OrgID)
+				assert.Equal(t, "owner", claims.OrgRole)
 				assert.WithinDuration(t, expiresAt, claims.ExpiresAt.Time, time.Second)
 				assert.WithinDuration(t, expiresAt, claims.IssuedAt.Add(tt.ttl), time.Second)
 			}
 		})
 	}
 }
+
+func TestValidateRejectsForeignIssuerAndAudience(t *testing.T) {
+	secret := randomString(jwt.MinSecretSize)
+	userId, _ := uuid.NewV7()
+
+	tests := []struct {
+		name     string
+		issuer   string
+		audience string
+	}{
+		{
+			"should return error when issuer does not match",
+			"other-issuer",
+			defaultAudience,
+		},
+		{
+			"should return error when audience does not match",
+			defaultIssuer,
+			"other-audience",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minter, err := jwt.NewTokenManager(secret, time.Minute, tt.issuer, tt.audience)
+			require.NoError(t, err)
+			validator, err := jwt.NewTokenManager(secret, time.Minute, defaultIssuer, defaultAudience)
+			require.NoError(t, err)
+
+			tokStr, _, err := minter.Generate(jwt.Identity{UserID: userId})
+			require.NoError(t, err)
+
+			_, err = validator.Validate(tokStr)
+			assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+		})
+	}
+}
@@ -3,17 +3,20 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
 	"github.com/justinas/nosurf"
 )
 
-func PreventCSRF(next http.Handler) http.Handler {
-	csrfHandler := nosurf.New(next)
-	csrfHandler.SetBaseCookie(http.Cookie{
-		HttpOnly: true,
-		Path:     "/",
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-	})
+func PreventCSRF(cookies cookie.Policy, exemptPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		csrfHandler := nosurf.New(next)
+		// SetBaseCookie replaces nosurf's defaults wholesale, so the lifetime
+		// it would have set has to be restored by hand.
+		baseCookie := cookies.Base(cookies.CSRFName(), true)
+		baseCookie.MaxAge = nosurf.MaxAge
+		csrfHandler.SetBaseCookie(baseCookie)
+		csrfHandler.ExemptPaths(exemptPaths...)
 
-	return csrfHandler
+		return csrfHandler
+	}
 }
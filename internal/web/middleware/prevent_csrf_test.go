@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+	"github.com/justinas/nosurf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreventCSRF(t *testing.T) {
+	cookies := cookie.NewPolicy(config.CookieConfig{Prefix: "__Host-", CSRFName: "csrf", Secure: true, SameSite: http.SameSiteLaxMode})
+	handler := middleware.PreventCSRF(cookies, "/exempt")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"should issue a token on safe requests", http.MethodGet, "/", http.StatusNoContent},
+		{"should reject unsafe requests without a token", http.MethodPost, "/", http.StatusBadRequest},
+		{"should let exempt paths through", http.MethodPost, "/exempt", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "https://example.com"+tt.path, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusNoContent {
+				return
+			}
+			res := w.Result()
+			require.Len(t, res.Cookies(), 1)
+			c := res.Cookies()[0]
+			assert.Equal(t, "__Host-csrf", c.Name)
+			assert.Equal(t, nosurf.MaxAge, c.MaxAge)
+			assert.True(t, c.HttpOnly)
+			assert.True(t, c.Secure)
+		})
+	}
+}
@@ -2,9 +2,13 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
 	"github.com/joaovictorsl/go-backend-template/internal/web"
 )
 
@@ -33,6 +37,12 @@ func Recover(next http.Handler) http.Handler {
 				)
 			}
 
+			var quotaErr plan.QuotaExceededError
+			if errors.As(castedErr, &quotaErr) && quotaErr.ResetsAt != nil {
+				retryAfter := int(time.Until(*quotaErr.ResetsAt).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			}
+
 			raw, _ := json.Marshal(err)
 			w.WriteHeader(err.Status())
 			w.Write(raw)
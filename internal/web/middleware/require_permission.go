@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type PermissionChecker interface {
+	HasPermissions(ctx context.Context, userId uuid.UUID, permissions ...string) (bool, error)
+}
+
+func RequirePermission(permissionChecker PermissionChecker, permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userId := request.GetUserId(r)
+
+			ok, err := permissionChecker.HasPermissions(r.Context(), userId, permissions...)
+			if err != nil {
+				slog.Error(
+					"checking permissions",
+					slog.Any("error", err),
+					slog.String("user_id", userId.String()),
+					slog.String("permissions", strings.Join(permissions, ",")),
+				)
+				web.HandleError(err)
+			}
+
+			if !ok {
+				web.HttpErrResponse(w, http.StatusForbidden, "you don't have permission to do this")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,9 +1,20 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/dpop"
 	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
 	"github.com/joaovictorsl/go-backend-template/internal/web/request"
 )
@@ -12,17 +23,67 @@ type JwtValidator interface {
 	Validate(tokenString string) (*jwt.Claims, error)
 }
 
-func RequiresAuthentication(jwtSecret string, jwtValidator JwtValidator) func(http.Handler) http.Handler {
+type RevocationChecker interface {
+	IsRevoked(claims *jwt.Claims) bool
+}
+
+type TokenRenewer interface {
+	Refresh(w http.ResponseWriter, r *http.Request) (string, error)
+}
+
+type ProofValidator interface {
+	Validate(r *http.Request, accessToken string) (string, error)
+}
+
+type AccountStatusChecker interface {
+	Status(ctx context.Context, userId uuid.UUID) (string, error)
+}
+
+func RequiresAuthentication(
+	cookies cookie.Policy,
+	jwtValidator JwtValidator,
+	revocations RevocationChecker,
+	tokenRenewer TokenRenewer,
+	proofValidator ProofValidator,
+	statusChecker AccountStatusChecker,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			c, err := r.Cookie("atok")
+			var claims *jwt.Claims
+			scheme, aTok, err := accessToken(r, cookies)
+			if err == nil {
+				claims, err = jwtValidator.Validate(aTok)
+			}
+
+			renewed := false
+			if tokenRenewer != nil && (errors.Is(err, http.ErrNoCookie) || errors.Is(err, jwt.ErrTokenExpired)) {
+				claims, err = renew(w, r, jwtValidator, tokenRenewer)
+				renewed = err == nil
+			}
+
 			if err != nil {
 				w.WriteHeader(http.StatusUnauthorized)
 				return
 			}
 
-			claims, err := jwtValidator.Validate(c.Value)
-			if err != nil {
+			// RFC 9449 section 7.1: bound tokens must not be downgraded to
+			// bearer ones, and unbound tokens must not be sent as DPoP.
+			if !renewed && scheme != "" && strings.EqualFold(scheme, dpop.Scheme) != (claims.Thumbprint() != "") {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`%s error="invalid_token"`, scheme))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if thumbprint := claims.Thumbprint(); thumbprint != "" && !renewed {
+				proofThumbprint, err := proofValidator.Validate(r, aTok)
+				if err != nil || proofThumbprint != thumbprint {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`%s error="invalid_dpop_proof"`, dpop.Scheme))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+			}
+
+			if revocations.IsRevoked(claims) {
 				w.WriteHeader(http.StatusUnauthorized)
 				return
 			}
@@ -39,8 +100,66 @@ func RequiresAuthentication(jwtSecret string, jwtValidator JwtValidator) func(ht
 				return
 			}
 
+			status, err := statusChecker.Status(r.Context(), userId)
+			if errors.Is(err, core.ErrNotFound) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			} else if err != nil {
+				slog.Error(
+					"checking account status",
+					slog.Any("error", err),
+					slog.String("user_id", userId.String()),
+				)
+				web.HandleError(err)
+			}
+
+			if err := auth.AccountStatusError(status); err != nil {
+				web.HttpErrResponse(w, http.StatusForbidden, err.Error())
+				return
+			}
+
 			request.WithUserId(r, userId)
+			request.WithOrgId(r, claims.OrgID)
+			request.WithClaims(r, claims)
+			request.WithPrincipal(r, policy.Principal{
+				UserId:  userId,
+				Roles:   claims.Roles,
+				OrgId:   claims.OrgID,
+				OrgRole: claims.OrgRole,
+			})
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// accessToken returns the token from the Authorization header along with its
+// scheme, or from the cookie with no scheme.
+func accessToken(r *http.Request, cookies cookie.Policy) (string, string, error) {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok {
+		if strings.EqualFold(scheme, dpop.Scheme) || strings.EqualFold(scheme, "Bearer") {
+			return scheme, token, nil
+		}
+	}
+
+	c, err := r.Cookie(cookies.AccessTokenName())
+	if err != nil {
+		return "", "", err
+	}
+	return "", c.Value, nil
+}
+
+func renew(w http.ResponseWriter, r *http.Request, jwtValidator JwtValidator, tokenRenewer TokenRenewer) (*jwt.Claims, error) {
+	aTok, err := tokenRenewer.Refresh(w, r)
+	if err != nil {
+		var authErr auth.Error
+		if !errors.As(err, &authErr) {
+			slog.Error(
+				"renewing access token",
+				slog.Any("error", err),
+			)
+		}
+		return nil, err
+	}
+
+	return jwtValidator.Validate(aTok)
+}
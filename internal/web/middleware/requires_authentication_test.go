@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/core/entity"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJwtValidator map[string]*jwt.Claims
+
+func (v fakeJwtValidator) Validate(tokenString string) (*jwt.Claims, error) {
+	claims, ok := v[tokenString]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+type noRevocations struct{}
+
+func (noRevocations) IsRevoked(claims *jwt.Claims) bool {
+	return false
+}
+
+type fixedProof string
+
+func (p fixedProof) Validate(r *http.Request, accessToken string) (string, error) {
+	return string(p), nil
+}
+
+type activeStatus struct{}
+
+func (activeStatus) Status(ctx context.Context, userId uuid.UUID) (string, error) {
+	return entity.UserStatusActive, nil
+}
+
+func TestRequiresAuthenticationScheme(t *testing.T) {
+	newClaims := func(cnf *jwt.Confirmation) *jwt.Claims {
+		return &jwt.Claims{
+			RegisteredClaims: gojwt.RegisteredClaims{Subject: uuid.NewString()},
+			Confirmation:     cnf,
+		}
+	}
+	validator := fakeJwtValidator{
+		"bearer": newClaims(nil),
+		"bound":  newClaims(&jwt.Confirmation{JwkThumbprint: "thumbprint"}),
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := middleware.RequiresAuthentication(
+		cookie.NewPolicy(config.CookieConfig{AccessTokenName: "atok"}),
+		validator,
+		noRevocations{},
+		nil,
+		fixedProof("thumbprint"),
+		activeStatus{},
+	)(next)
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"should accept unbound token as bearer", "Bearer bearer", http.StatusNoContent},
+		{"should accept bound token as dpop", "DPoP bound", http.StatusNoContent},
+		{"should reject bound token as bearer", "Bearer bound", http.StatusUnauthorized},
+		{"should reject unbound token as dpop", "DPoP bearer", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/me", nil)
+			r.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
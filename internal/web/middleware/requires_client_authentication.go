@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+func RequiresClientAuthentication(clients map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientId, clientSecret, ok := r.BasicAuth()
+			if !ok {
+				unauthorizedClient(w)
+				return
+			}
+
+			secret, ok := clients[clientId]
+			if !ok || subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret)) != 1 {
+				unauthorizedClient(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorizedClient(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="clients"`)
+	w.WriteHeader(http.StatusUnauthorized)
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiresClientAuthentication(t *testing.T) {
+	clients := map[string]string{"Billing-Service": "secret"}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := middleware.RequiresClientAuthentication(clients)(next)
+
+	tests := []struct {
+		name   string
+		auth   bool
+		id     string
+		secret string
+		status int
+	}{
+		{"should accept known client with its secret", true, "Billing-Service", "secret", http.StatusNoContent},
+		{"should reject request without credentials", false, "", "", http.StatusUnauthorized},
+		{"should reject unknown client", true, "other", "secret", http.StatusUnauthorized},
+		{"should reject wrong secret", true, "Billing-Service", "wrong", http.StatusUnauthorized},
+		{"should match client ids case sensitively", true, "billing-service", "secret", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/auth/introspect", nil)
+			if tt.auth {
+				r.SetBasicAuth(tt.id, tt.secret)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="clients"`, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/joaovictorsl/go-backend-template/internal/web"
+	"github.com/joaovictorsl/go-backend-template/internal/web/request"
+)
+
+type reauthenticationRequired struct {
+	Error             string `json:"error"`
+	Message           string `json:"message"`
+	MaxAge            int64  `json:"max_age"`
+	ReauthenticateUrl string `json:"reauthenticate_url,omitempty"`
+}
+
+func RequiresRecentAuthentication(maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := request.GetClaims(r)
+			if claims.AuthTime != nil && time.Since(claims.AuthTime.Time) <= maxAge {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			resp := reauthenticationRequired{
+				Error:   "reauthentication_required",
+				Message: "This operation requires you to sign in again",
+				MaxAge:  int64(maxAge.Seconds()),
+			}
+			if len(claims.AuthMethods) > 0 {
+				resp.ReauthenticateUrl = fmt.Sprintf("/oauth/%s?reauth=true", claims.AuthMethods[0])
+			}
+
+			w.Header().Set(
+				"WWW-Authenticate",
+				fmt.Sprintf(`Bearer error="insufficient_user_authentication", max_age=%d`, resp.MaxAge),
+			)
+			web.JsonResponse(w, http.StatusUnauthorized, resp)
+		})
+	}
+}
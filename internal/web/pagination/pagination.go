@@ -0,0 +1,224 @@
+package pagination
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+)
+
+const (
+	LimitParam  = "limit"
+	SortParam   = "sort"
+	FilterParam = "filter"
+	CursorParam = "cursor"
+)
+
+var (
+	ErrInvalidLimit  = fmt.Errorf("%w: limit must be a positive integer", core.ErrInvalid)
+	ErrInvalidCursor = fmt.Errorf("%w: invalid cursor", core.ErrInvalid)
+)
+
+// Options describes a list endpoint.
+type Options struct {
+	Fields       page.Fields
+	DefaultSort  []page.Order
+	DefaultLimit int
+	MaxLimit     int
+	// Params are other query parameters that narrow the list. Cursors are
+	// bound to their values like they are to the sort and filter.
+	Params []string
+}
+
+// Codec turns cursors into opaque tokens. Tokens are signed so clients
+// cannot forge positions, and bound to the sort and filter they were issued
+// for so they cannot be replayed against another query.
+type Codec struct {
+	key []byte
+}
+
+func NewCodec(key []byte) *Codec {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("pagination cursor"))
+	return &Codec{key: mac.Sum(nil)}
+}
+
+type cursorPayload struct {
+	Query    string `json:"q"`
+	Values   []any  `json:"v"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// ParseRequest reads the limit, sort, filter and cursor query parameters.
+// A limit above the maximum is lowered to it.
+func (c *Codec) ParseRequest(query url.Values, opts Options) (page.Request, error) {
+	req := page.Request{Limit: opts.DefaultLimit}
+
+	if raw := query.Get(LimitParam); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return page.Request{}, ErrInvalidLimit
+		}
+		req.Limit = limit
+	}
+	req.Limit = min(req.Limit, opts.MaxLimit)
+
+	var err error
+	if req.Sort, err = page.ParseSort(query.Get(SortParam), opts.Fields, opts.DefaultSort); err != nil {
+		return page.Request{}, err
+	}
+	if req.Filter, err = page.ParseFilter(query.Get(FilterParam), opts.Fields); err != nil {
+		return page.Request{}, err
+	}
+
+	scope := url.Values{}
+	for _, name := range opts.Params {
+		if values, ok := query[name]; ok {
+			scope[name] = values
+		}
+	}
+	req.Scope = scope.Encode()
+
+	if raw := query.Get(CursorParam); raw != "" {
+		if req.Cursor, err = c.Decode(raw, req, opts.Fields); err != nil {
+			return page.Request{}, err
+		}
+	}
+
+	return req, nil
+}
+
+func (c *Codec) Encode(cursor *page.Cursor, req page.Request) string {
+	if cursor == nil {
+		return ""
+	}
+
+	values := make([]any, 0, len(cursor.Values))
+	for _, v := range cursor.Values {
+		switch v := v.(type) {
+		case time.Time:
+			values = append(values, v.UTC().Format(time.RFC3339Nano))
+		case uuid.UUID:
+			values = append(values, v.String())
+		default:
+			values = append(values, v)
+		}
+	}
+
+	payload, _ := json.Marshal(cursorPayload{
+		Query:    fingerprint(req),
+		Values:   values,
+		Backward: cursor.Backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + c.sign(payload)
+}
+
+// Decode verifies a token and converts its values back to the types of the
+// sort fields of req.
+func (c *Codec) Decode(token string, req page.Request, fields page.Fields) (*page.Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(c.sign(payload))
+	if !hmac.Equal(got, want) {
+		return nil, ErrInvalidCursor
+	}
+
+	var p cursorPayload
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&p); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if p.Query != fingerprint(req) || len(p.Values) != len(req.Sort) {
+		return nil, fmt.Errorf("%w: it was issued for another sort or filter", ErrInvalidCursor)
+	}
+
+	cursor := &page.Cursor{Backward: p.Backward, Values: make([]any, 0, len(p.Values))}
+	for i, o := range req.Sort {
+		v, err := decodeValue(p.Values[i], fields[o.Field].Type)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor.Values = append(cursor.Values, v)
+	}
+	return cursor, nil
+}
+
+func decodeValue(v any, t page.Type) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return t.Parse(v)
+	case json.Number:
+		return t.Parse(v.String())
+	case bool:
+		return t.Parse(strconv.FormatBool(v))
+	default:
+		return nil, fmt.Errorf("unexpected cursor value %T", v)
+	}
+}
+
+func (c *Codec) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func fingerprint(req page.Request) string {
+	filter := ""
+	if req.Filter != nil {
+		filter = req.Filter.String()
+	}
+
+	sum := sha256.Sum256([]byte(page.SortString(req.Sort) + "\n" + filter + "\n" + req.Scope))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+type Meta struct {
+	Limit int    `json:"limit"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// Envelope is the response body of every paginated list. Next and Prev are
+// passed back as the cursor parameter, along with the same sort and filter.
+type Envelope[T any] struct {
+	Data []T  `json:"data"`
+	Page Meta `json:"page"`
+}
+
+func NewEnvelope[T, R any](c *Codec, req page.Request, p page.Page[T], convert func(T) R) Envelope[R] {
+	data := make([]R, 0, len(p.Items))
+	for _, item := range p.Items {
+		data = append(data, convert(item))
+	}
+
+	return Envelope[R]{
+		Data: data,
+		Page: Meta{
+			Limit: req.Limit,
+			Next:  c.Encode(p.Next, req),
+			Prev:  c.Encode(p.Prev, req),
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package pagination_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/core"
+	"github.com/joaovictorsl/go-backend-template/internal/core/page"
+	"github.com/joaovictorsl/go-backend-template/internal/web/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var opts = pagination.Options{
+	Fields: page.Fields{
+		page.IdField: {Type: page.TypeUUID, Sortable: true},
+		"email":      {Type: page.TypeString, Sortable: true, Filterable: true},
+		"created_at": {Type: page.TypeTime, Sortable: true, Filterable: true},
+		"logins":     {Type: page.TypeInt, Sortable: true},
+	},
+	DefaultSort:  []page.Order{{Field: "created_at", Desc: true}},
+	DefaultLimit: 20,
+	MaxLimit:     50,
+	Params:       []string{"status"},
+}
+
+func TestParseRequest(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+
+	req, err := c.ParseRequest(url.Values{}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 20, req.Limit)
+	assert.Equal(t, []page.Order{{Field: "created_at", Desc: true}, {Field: "id", Desc: true}}, req.Sort)
+	assert.Nil(t, req.Filter)
+	assert.Nil(t, req.Cursor)
+
+	req, err = c.ParseRequest(url.Values{
+		"limit":  {"500"},
+		"sort":   {"email"},
+		"filter": {`email co "x"`},
+	}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 50, req.Limit)
+	assert.Equal(t, []page.Order{{Field: "email"}, {Field: "id"}}, req.Sort)
+	assert.NotNil(t, req.Filter)
+}
+
+func TestParseRequestRejectsInvalidParams(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"should reject non numeric limit", url.Values{"limit": {"ten"}}},
+		{"should reject zero limit", url.Values{"limit": {"0"}}},
+		{"should reject unknown sort", url.Values{"sort": {"password"}}},
+		{"should reject invalid filter", url.Values{"filter": {"email eq"}}},
+		{"should reject garbage cursor", url.Values{"cursor": {"abc"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.ParseRequest(tt.query, opts)
+			assert.ErrorIs(t, err, core.ErrInvalid)
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+	query := url.Values{"sort": {"-created_at,logins,email"}, "filter": {`email sw "a"`}}
+
+	req, err := c.ParseRequest(query, opts)
+	require.NoError(t, err)
+
+	cursor := &page.Cursor{
+		Values:   []any{time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC), int64(7), "a@b.c", uuid.New()},
+		Backward: true,
+	}
+	query.Set("cursor", c.Encode(cursor, req))
+
+	req, err = c.ParseRequest(query, opts)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, req.Cursor)
+}
+
+func TestCursorIsBoundToItsQuery(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+
+	req, err := c.ParseRequest(url.Values{"status": {"active"}}, opts)
+	require.NoError(t, err)
+	token := c.Encode(&page.Cursor{Values: []any{time.Now(), uuid.New()}}, req)
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"should reject cursor with another sort", url.Values{"sort": {"created_at"}, "status": {"active"}}},
+		{"should reject cursor with another filter", url.Values{"filter": {`email eq "x"`}, "status": {"active"}}},
+		{"should reject cursor with another param", url.Values{"status": {"suspended"}}},
+		{"should reject cursor without its param", url.Values{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Set("cursor", token)
+			_, err := c.ParseRequest(tt.query, opts)
+			assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+		})
+	}
+}
+
+func TestCursorRejectsTampering(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+
+	req, err := c.ParseRequest(url.Values{}, opts)
+	require.NoError(t, err)
+	token := c.Encode(&page.Cursor{Values: []any{time.Now(), uuid.New()}}, req)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := pagination.NewCodec([]byte("other")).Encode(&page.Cursor{Values: []any{time.Now(), uuid.New()}}, req)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for _, token := range []string{
+		payload,
+		forgedPayload + "." + signature,
+		forged,
+	} {
+		_, err := c.Decode(token, req, opts.Fields)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	c := pagination.NewCodec([]byte("secret"))
+	req := page.Request{Limit: 2, Sort: []page.Order{{Field: "logins"}, {Field: "id"}}}
+	id := uuid.New()
+
+	p := page.Page[int]{Items: []int{1, 2}, Next: &page.Cursor{Values: []any{int64(2), id}}}
+	env := pagination.NewEnvelope(c, req, p, func(i int) int { return i * 10 })
+
+	assert.Equal(t, []int{10, 20}, env.Data)
+	assert.Equal(t, 2, env.Page.Limit)
+	assert.Empty(t, env.Page.Prev)
+
+	cursor, err := c.Decode(env.Page.Next, req, opts.Fields)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(2), id}, cursor.Values)
+}
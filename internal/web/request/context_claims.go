@@ -0,0 +1,34 @@
+package request
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+)
+
+func WithClaims(r *http.Request, claims *jwt.Claims) {
+	*r = *r.WithContext(context.WithValue(r.Context(), "claims", claims))
+}
+
+func GetClaims(r *http.Request) *jwt.Claims {
+	claims := r.Context().Value("claims").(*jwt.Claims)
+	return claims
+}
+
+func GetSessionId(r *http.Request) uuid.UUID {
+	return GetClaims(r).SessionID
+}
+
+func GetRoles(r *http.Request) []string {
+	return GetClaims(r).Roles
+}
+
+func GetIssuer(r *http.Request) string {
+	return GetClaims(r).Issuer
+}
+
+func GetAudience(r *http.Request) []string {
+	return GetClaims(r).Audience
+}
@@ -0,0 +1,17 @@
+package request
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func WithOrgId(r *http.Request, orgId uuid.UUID) {
+	*r = *r.WithContext(context.WithValue(r.Context(), "org_id", orgId))
+}
+
+func GetOrgId(r *http.Request) uuid.UUID {
+	id, _ := r.Context().Value("org_id").(uuid.UUID)
+	return id
+}
@@ -0,0 +1,11 @@
+package request
+
+import (
+	"net/http"
+
+	"github.com/joaovictorsl/go-backend-template/internal/core/policy"
+)
+
+func WithPrincipal(r *http.Request, p policy.Principal) {
+	*r = *r.WithContext(policy.WithPrincipal(r.Context(), p))
+}
@@ -0,0 +1,17 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func JsonResponse(w http.ResponseWriter, status int, v any) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		HandleError(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(raw)
+}
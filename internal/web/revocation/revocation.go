@@ -0,0 +1,191 @@
+package revocation
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	corerevocation "github.com/joaovictorsl/go-backend-template/internal/core/revocation"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+)
+
+const (
+	listenRetryDelay = 5 * time.Second
+	pruneInterval    = time.Minute
+)
+
+type List struct {
+	store  corerevocation.Store
+	maxAge time.Duration
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[uuid.UUID]time.Time
+}
+
+func New(store corerevocation.Store, accessTokenTTL time.Duration) *List {
+	return &List{
+		store:  store,
+		maxAge: accessTokenTTL,
+		tokens: make(map[string]time.Time),
+		users:  make(map[uuid.UUID]time.Time),
+	}
+}
+
+func (l *List) Load(ctx context.Context) error {
+	now := time.Now()
+	tokens, users, err := l.store.GetActive(ctx, now, now.Add(-l.maxAge))
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = make(map[string]time.Time, len(tokens))
+	for _, t := range tokens {
+		l.tokens[t.TokenId] = t.ExpiresAt
+	}
+
+	l.users = make(map[uuid.UUID]time.Time, len(users))
+	for _, u := range users {
+		l.users[u.UserId] = u.RevokedBefore
+	}
+
+	return nil
+}
+
+func (l *List) Run(ctx context.Context) {
+	go l.prune(ctx)
+
+	for {
+		err := l.store.Listen(ctx, l.reload(ctx), l.apply)
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error(
+			"listening for token revocations",
+			slog.Any("error", err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (l *List) RevokeToken(ctx context.Context, claims *jwt.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	t := corerevocation.RevokedToken{
+		TokenId:   claims.ID,
+		UserId:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := l.store.InsertToken(ctx, t); err != nil {
+		return err
+	}
+
+	l.apply(corerevocation.Event{Token: &t})
+	return nil
+}
+
+// RevokeUser revokes the access tokens of the user issued strictly before
+// before. Token issue times have whole second precision, so a cutoff
+// truncated to the second keeps tokens issued within that second valid,
+// which lets the caller be reissued a token right away.
+func (l *List) RevokeUser(ctx context.Context, userId uuid.UUID, before time.Time) error {
+	u := corerevocation.RevokedUser{
+		UserId:        userId,
+		RevokedBefore: before,
+	}
+	if err := l.store.InsertUser(ctx, u); err != nil {
+		return err
+	}
+
+	l.apply(corerevocation.Event{User: &u})
+	return nil
+}
+
+func (l *List) IsRevoked(claims *jwt.Claims) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if _, ok := l.tokens[claims.ID]; ok {
+		return true
+	}
+
+	before, ok := l.users[claims.UserID]
+	if !ok {
+		return false
+	}
+
+	return claims.IssuedAt == nil || claims.IssuedAt.Before(before)
+}
+
+func (l *List) apply(e corerevocation.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e.Token != nil {
+		l.tokens[e.Token.TokenId] = e.Token.ExpiresAt
+	}
+
+	if e.User != nil {
+		if before, ok := l.users[e.User.UserId]; !ok || e.User.RevokedBefore.After(before) {
+			l.users[e.User.UserId] = e.User.RevokedBefore
+		}
+	}
+}
+
+func (l *List) reload(ctx context.Context) func() {
+	return func() {
+		if err := l.Load(ctx); err != nil {
+			slog.Error(
+				"reloading token revocations",
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+func (l *List) prune(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			since := now.Add(-l.maxAge)
+
+			l.mu.Lock()
+			for jti, expiresAt := range l.tokens {
+				if now.After(expiresAt) {
+					delete(l.tokens, jti)
+				}
+			}
+			for userId, before := range l.users {
+				if before.Before(since) {
+					delete(l.users, userId)
+				}
+			}
+			l.mu.Unlock()
+
+			if err := l.store.DeleteExpired(ctx, now, since); err != nil {
+				slog.Error(
+					"deleting expired token revocations",
+					slog.Any("error", err),
+				)
+			}
+		}
+	}
+}
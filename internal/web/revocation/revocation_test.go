@@ -0,0 +1,99 @@
+package revocation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	corerevocation "github.com/joaovictorsl/go-backend-template/internal/core/revocation"
+	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
+	"github.com/joaovictorsl/go-backend-template/internal/web/revocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	tokens []corerevocation.RevokedToken
+	users  []corerevocation.RevokedUser
+}
+
+func (s *fakeStore) InsertToken(ctx context.Context, t corerevocation.RevokedToken) error {
+	s.tokens = append(s.tokens, t)
+	return nil
+}
+
+func (s *fakeStore) InsertUser(ctx context.Context, u corerevocation.RevokedUser) error {
+	s.users = append(s.users, u)
+	return nil
+}
+
+func (s *fakeStore) GetActive(ctx context.Context, now time.Time, since time.Time) ([]corerevocation.RevokedToken, []corerevocation.RevokedUser, error) {
+	return s.tokens, s.users, nil
+}
+
+func (s *fakeStore) DeleteExpired(ctx context.Context, now time.Time, since time.Time) error {
+	return nil
+}
+
+func (s *fakeStore) Listen(ctx context.Context, ready func(), handle func(corerevocation.Event)) error {
+	ready()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newClaims(userId uuid.UUID, issuedAt time.Time) *jwt.Claims {
+	return &jwt.Claims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  gojwt.NewNumericDate(issuedAt),
+			ExpiresAt: gojwt.NewNumericDate(issuedAt.Add(time.Minute)),
+		},
+		UserID: userId,
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	l := revocation.New(&fakeStore{}, time.Minute)
+	userId := uuid.New()
+	revoked := newClaims(userId, time.Now())
+	other := newClaims(userId, time.Now())
+
+	require.NoError(t, l.RevokeToken(context.Background(), revoked))
+
+	assert.True(t, l.IsRevoked(revoked))
+	assert.False(t, l.IsRevoked(other))
+}
+
+func TestRevokeUser(t *testing.T) {
+	l := revocation.New(&fakeStore{}, time.Minute)
+	userId := uuid.New()
+	now := time.Now().Truncate(time.Second)
+	before := newClaims(userId, now.Add(-10*time.Second))
+	sameSecond := newClaims(userId, now)
+	after := newClaims(userId, now.Add(10*time.Second))
+	otherUser := newClaims(uuid.New(), now.Add(-10*time.Second))
+
+	require.NoError(t, l.RevokeUser(context.Background(), userId, now))
+
+	assert.True(t, l.IsRevoked(before))
+	assert.False(t, l.IsRevoked(sameSecond))
+	assert.False(t, l.IsRevoked(after))
+	assert.False(t, l.IsRevoked(otherUser))
+}
+
+func TestLoad(t *testing.T) {
+	userId := uuid.New()
+	claims := newClaims(userId, time.Now())
+	store := &fakeStore{
+		tokens: []corerevocation.RevokedToken{
+			{TokenId: claims.ID, UserId: userId, ExpiresAt: claims.ExpiresAt.Time},
+		},
+	}
+	l := revocation.New(store, time.Minute)
+
+	assert.False(t, l.IsRevoked(claims))
+	require.NoError(t, l.Load(context.Background()))
+	assert.True(t, l.IsRevoked(claims))
+}
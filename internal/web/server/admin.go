@@ -0,0 +1,43 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/joaovictorsl/go-backend-template/internal/web/handler"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+)
+
+func (app *Server) setupAdmin() {
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionUsersRead))
+
+		r.Get("/admin/users", handler.HandleAdminListUsers(app.AdminUseCase, app.Cursors))
+		r.Get("/admin/users/{id}", handler.HandleAdminGetUser(app.AdminUseCase))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionUsersManage))
+
+			r.Post("/admin/users/{id}/suspend", handler.HandleAdminSuspendUser(app.AdminUseCase))
+			r.Post("/admin/users/{id}/activate", handler.HandleAdminActivateUser(app.AdminUseCase))
+			r.Post("/admin/users/{id}/signout", handler.HandleAdminSignOutUser(app.AdminUseCase))
+		})
+	})
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionFlagsManage))
+
+		r.Get("/admin/flags", handler.HandleAdminListFlags(app.Flags))
+		r.Put("/admin/flags/{key}", handler.HandleAdminPutFlag(app.Flags))
+		r.Delete("/admin/flags/{key}", handler.HandleAdminDeleteFlag(app.Flags))
+	})
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionPlansManage))
+
+		r.Put("/admin/users/{id}/plan", handler.HandleAdminSetUserPlan(app.Plans))
+		r.Put("/admin/orgs/{id}/plan", handler.HandleAdminSetOrganizationPlan(app.Plans))
+	})
+}
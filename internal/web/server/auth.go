@@ -1,7 +1,13 @@
 package server
 
 import (
+	"github.com/go-chi/chi/v5"
 	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+)
+
+const (
+	introspectionPath = "/oauth/introspect"
 )
 
 func (app *Server) setupAuth() {
@@ -9,15 +15,24 @@ func (app *Server) setupAuth() {
 	app.mux.Get("/oauth/{provider}", auth.HandleOAuth(providers, app.OAuthStore))
 	app.mux.Get("/oauth/{provider}/callback", auth.HandleOAuthCallback(
 		providers,
-		app.Config.RefreshTokenTTL,
 		app.OAuthStore,
 		app.UserStore,
-		app.RefreshTokenStore,
-		app.JwtManager,
+		app.UserService,
+		app.Avatars,
+		app.OrgUseCase,
+		app.sessions,
 	))
-	app.mux.Get("/auth/refresh", auth.HandleRefresh(
-		app.Config.RefreshTokenTTL,
+	app.mux.Get("/auth/refresh", auth.HandleRefresh(app.sessions))
+	app.mux.Post("/auth/signout", auth.HandleSignOut(
 		app.RefreshTokenStore,
 		app.JwtManager,
+		app.Revocations,
+		app.cookies,
 	))
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(middleware.RequiresClientAuthentication(app.Config.MachineClients))
+
+		r.Post(introspectionPath, auth.HandleIntrospect(app.JwtManager, app.Revocations))
+	})
 }
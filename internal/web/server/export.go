@@ -0,0 +1,22 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/joaovictorsl/go-backend-template/internal/web/handler"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+)
+
+func (app *Server) setupExport() {
+	app.mux.Get("/exports/{id}/download", handler.HandleDownloadExport(app.Exports))
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionProfileRead))
+
+		r.Get("/users/me/exports/{id}", handler.HandleGetExport(app.Exports))
+
+		r.With(middleware.RequiresRecentAuthentication(app.Config.ReauthenticationMaxAge)).
+			Post("/users/me/exports", handler.HandleRequestExport(app.Exports))
+	})
+}
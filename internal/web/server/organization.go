@@ -0,0 +1,27 @@
+package server
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/handler"
+)
+
+func (app *Server) setupOrganization() {
+	providers := slices.Sorted(maps.Keys(auth.GetProviders(app.Config)))
+	app.mux.Get("/invitations/accept", handler.HandlePreviewInvitation(app.OrgUseCase, providers))
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+
+		r.Post("/orgs", handler.HandleCreateOrganization(app.OrgUseCase))
+		r.Get("/orgs", handler.HandleListOrganizations(app.OrgUseCase))
+		r.Post("/orgs/{id}/switch", handler.HandleSwitchOrganization(app.OrgUseCase, app.sessions))
+		r.Post("/orgs/{id}/invitations", handler.HandleCreateInvitation(app.OrgUseCase))
+		r.Get("/orgs/{id}/invitations", handler.HandleListInvitations(app.OrgUseCase))
+		r.Delete("/orgs/{id}/invitations/{invitationId}", handler.HandleRevokeInvitation(app.OrgUseCase))
+		r.Post("/invitations/accept", handler.HandleAcceptInvitation(app.OrgUseCase, app.sessions))
+	})
+}
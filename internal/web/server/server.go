@@ -10,23 +10,51 @@ import (
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/joaovictorsl/go-backend-template/internal/config"
+	"github.com/joaovictorsl/go-backend-template/internal/core/avatar"
+	"github.com/joaovictorsl/go-backend-template/internal/core/export"
+	"github.com/joaovictorsl/go-backend-template/internal/core/flags"
+	orgusecase "github.com/joaovictorsl/go-backend-template/internal/core/organization/usecase"
+	"github.com/joaovictorsl/go-backend-template/internal/core/plan"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
+	"github.com/joaovictorsl/go-backend-template/internal/core/settings"
+	userservice "github.com/joaovictorsl/go-backend-template/internal/core/user/service"
 	userusecase "github.com/joaovictorsl/go-backend-template/internal/core/user/usecase"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/inmemory"
 	"github.com/joaovictorsl/go-backend-template/internal/storage/postgres"
+	"github.com/joaovictorsl/go-backend-template/internal/web/auth"
+	"github.com/joaovictorsl/go-backend-template/internal/web/cookie"
+	"github.com/joaovictorsl/go-backend-template/internal/web/dpop"
 	"github.com/joaovictorsl/go-backend-template/internal/web/jwt"
 	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
+	"github.com/joaovictorsl/go-backend-template/internal/web/pagination"
+	"github.com/joaovictorsl/go-backend-template/internal/web/revocation"
 )
 
 type Server struct {
 	mux            *chi.Mux
 	authMiddleware func(http.Handler) http.Handler
+	sessions       *auth.SessionManager
+	cookies        cookie.Policy
 
 	Config            *config.Config
 	OAuthStore        *inmemory.KVCache
 	RefreshTokenStore *postgres.RefreshTokenRepository
 	UserStore         *postgres.UserRepository
 	JwtManager        *jwt.TokenManager
+	Revocations       *revocation.List
+	ProofValidator    *dpop.Validator
+	Permissions       *rbac.Resolver
+	Flags             *flags.Service
+	OrganizationStore *postgres.OrganizationRepository
+	UserService       *userservice.Service
 	UserUseCase       *userusecase.UseCase
+	AdminUseCase      *userusecase.AdminUseCase
+	OrgUseCase        *orgusecase.UseCase
+	Plans             *plan.Service
+	Exports           *export.Service
+	Avatars           *avatar.Service
+	Settings          *settings.Service
+	Cursors           *pagination.Codec
 }
 
 func (app *Server) Run(addr string) {
@@ -66,6 +94,8 @@ func (app *Server) Run(addr string) {
 }
 
 func (app *Server) SetupRoutes() {
+	app.cookies = cookie.NewPolicy(app.Config.Cookies)
+
 	r := chi.NewRouter()
 	if !app.Config.IsProd() {
 		r.Use(middleware.Logger)
@@ -73,12 +103,45 @@ func (app *Server) SetupRoutes() {
 	r.Use(chimiddleware.Timeout(app.Config.RequestTimeout))
 	r.Use(middleware.Recover)
 	if app.Config.IsProd() {
-		r.Use(middleware.PreventCSRF)
+		r.Use(middleware.PreventCSRF(app.cookies, introspectionPath))
 	}
 
 	app.mux = r
-	app.authMiddleware = middleware.RequiresAuthentication(app.Config.JwtSecret, app.JwtManager)
+	app.sessions = auth.NewSessionManager(
+		auth.SessionPolicy{
+			IdleTimeout:         app.Config.SessionIdleTimeout,
+			AbsoluteLifetime:    app.Config.SessionAbsoluteLifetime,
+			RotationGracePeriod: app.Config.RefreshTokenGracePeriod,
+		},
+		auth.SessionLimit{
+			MaxPerUser: app.Config.SessionMaxPerUser,
+			Policy:     app.Config.SessionLimitPolicy,
+		},
+		app.RefreshTokenStore,
+		app.JwtManager,
+		app.ProofValidator,
+		app.Permissions,
+		app.OrganizationStore,
+		app.UserService,
+		app.cookies,
+	)
+
+	var tokenRenewer middleware.TokenRenewer
+	if app.Config.TransparentTokenRenewal {
+		tokenRenewer = app.sessions
+	}
+	app.authMiddleware = middleware.RequiresAuthentication(
+		app.cookies,
+		app.JwtManager,
+		app.Revocations,
+		tokenRenewer,
+		app.ProofValidator,
+		app.UserService,
+	)
 
 	app.setupUser()
+	app.setupExport()
+	app.setupOrganization()
+	app.setupAdmin()
 	app.setupAuth()
 }
@@ -2,13 +2,40 @@ package server
 
 import (
 	"github.com/go-chi/chi/v5"
+	"github.com/joaovictorsl/go-backend-template/internal/core/rbac"
 	"github.com/joaovictorsl/go-backend-template/internal/web/handler"
+	"github.com/joaovictorsl/go-backend-template/internal/web/middleware"
 )
 
 func (app *Server) setupUser() {
 	app.mux.Group(func(r chi.Router) {
 		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionProfileRead))
 
 		r.Get("/users/me", handler.HandleGetUser(app.UserUseCase))
+		r.Get("/users/me/flags", handler.HandleGetMyFlags(app.Flags))
+		r.Get("/users/me/usage", handler.HandleGetUsage(app.Plans))
+		r.Get("/users/me/settings", handler.HandleGetSettings(app.Settings))
+		r.Get("/users/{id}/avatar", handler.HandleGetAvatar(app.Avatars, app.Config.AvatarCacheMaxAge))
+	})
+
+	app.mux.Group(func(r chi.Router) {
+		r.Use(app.authMiddleware)
+		r.Use(middleware.RequirePermission(app.Permissions, rbac.PermissionProfileWrite))
+
+		r.Patch("/users/me", handler.HandleUpdateUser(app.UserUseCase))
+		r.Put("/users/me/settings", handler.HandleUpdateSettings(app.Settings))
+
+		r.Put("/users/me/avatar", handler.HandleUploadAvatar(app.Avatars, app.Config.AvatarMaxUploadBytes))
+		r.Delete("/users/me/avatar", handler.HandleDeleteAvatar(app.Avatars))
+
+		r.Post("/users/me/email/confirm", handler.HandleConfirmEmailChange(app.UserUseCase, app.sessions))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequiresRecentAuthentication(app.Config.ReauthenticationMaxAge))
+
+			r.Delete("/users/me", handler.HandleDeleteUser(app.UserUseCase, app.cookies))
+			r.Post("/users/me/email", handler.HandleRequestEmailChange(app.UserUseCase))
+		})
 	})
 }
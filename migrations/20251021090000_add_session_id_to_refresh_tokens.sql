@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+ADD COLUMN session_id UUID NOT NULL DEFAULT gen_random_uuid();
+
+ALTER TABLE refresh_tokens
+ALTER COLUMN session_id DROP DEFAULT;
+
+CREATE UNIQUE INDEX refresh_tokens_session_id_idx ON refresh_tokens (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX refresh_tokens_session_id_idx;
+
+ALTER TABLE refresh_tokens
+DROP COLUMN session_id;
+-- +goose StatementEnd
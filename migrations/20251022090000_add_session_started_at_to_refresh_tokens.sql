@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+ADD COLUMN session_started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+DROP COLUMN session_started_at;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+ADD COLUMN dpop_jkt VARCHAR(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+DROP COLUMN dpop_jkt;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+ADD COLUMN auth_time TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+ADD COLUMN auth_method VARCHAR(20) DEFAULT '' NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+DROP COLUMN auth_method,
+DROP COLUMN auth_time;
+-- +goose StatementEnd
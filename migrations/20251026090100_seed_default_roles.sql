@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO roles (name)
+VALUES ('user'), ('admin');
+
+INSERT INTO permissions (name)
+VALUES ('profile:read'), ('profile:write'), ('users:read'), ('users:manage');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN ('profile:read', 'profile:write')
+WHERE r.name = 'user';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin';
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id
+FROM users u
+JOIN roles r ON r.name = 'user';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM roles
+WHERE name IN ('user', 'admin');
+
+DELETE FROM permissions
+WHERE name IN ('profile:read', 'profile:write', 'users:read', 'users:manage');
+-- +goose StatementEnd
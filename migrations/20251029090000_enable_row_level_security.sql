@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION app_user_id() RETURNS UUID
+LANGUAGE sql STABLE
+AS $$ SELECT NULLIF(current_setting('app.user_id', true), '')::uuid $$;
+
+CREATE FUNCTION app_org_id() RETURNS UUID
+LANGUAGE sql STABLE
+AS $$ SELECT NULLIF(current_setting('app.org_id', true), '')::uuid $$;
+
+ALTER TABLE organizations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organizations FORCE ROW LEVEL SECURITY;
+CREATE POLICY organizations_tenant_isolation ON organizations
+USING (
+  app_user_id() IS NULL
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+  OR EXISTS (
+    SELECT 1
+    FROM memberships m
+    WHERE m.organization_id = organizations.id AND m.user_id = app_user_id()
+  )
+)
+WITH CHECK (
+  app_user_id() IS NULL
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+);
+
+ALTER TABLE memberships ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memberships FORCE ROW LEVEL SECURITY;
+CREATE POLICY memberships_tenant_isolation ON memberships
+USING (
+  app_user_id() IS NULL
+  OR organization_id = app_org_id()
+  OR user_id = app_user_id()
+);
+
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invitations FORCE ROW LEVEL SECURITY;
+CREATE POLICY invitations_tenant_isolation ON invitations
+USING (
+  app_user_id() IS NULL
+  OR organization_id = app_org_id()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY invitations_tenant_isolation ON invitations;
+ALTER TABLE invitations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE invitations DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY memberships_tenant_isolation ON memberships;
+ALTER TABLE memberships NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE memberships DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY organizations_tenant_isolation ON organizations;
+ALTER TABLE organizations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE organizations DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION app_org_id();
+DROP FUNCTION app_user_id();
+-- +goose StatementEnd
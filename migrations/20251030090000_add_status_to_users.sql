@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN status VARCHAR(20) DEFAULT 'active' NOT NULL,
+ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX users_created_at_idx ON users (created_at);
+CREATE INDEX users_status_idx ON users (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX users_status_idx;
+DROP INDEX users_created_at_idx;
+
+ALTER TABLE users
+DROP COLUMN status_changed_at,
+DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE feature_flags (
+  key VARCHAR(100) PRIMARY KEY,
+  description TEXT DEFAULT '' NOT NULL,
+  enabled BOOLEAN DEFAULT FALSE NOT NULL,
+  percentage SMALLINT DEFAULT 0 NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+  environments TEXT[] DEFAULT '{}' NOT NULL,
+  user_ids UUID[] DEFAULT '{}' NOT NULL,
+  organization_ids UUID[] DEFAULT '{}' NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+INSERT INTO permissions (name)
+VALUES ('flags:manage');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'flags:manage'
+WHERE r.name = 'admin';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions
+WHERE name = 'flags:manage';
+
+DROP TABLE feature_flags;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE plans (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  key VARCHAR(64) UNIQUE NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE plan_entitlements (
+  plan_id UUID NOT NULL,
+  key VARCHAR(64) NOT NULL,
+  quota BIGINT NOT NULL CHECK (quota >= -1),
+  period VARCHAR(10) NOT NULL CHECK (period IN ('day', 'month', 'none')),
+  PRIMARY KEY (plan_id, key),
+  FOREIGN KEY (plan_id) REFERENCES plans(id) ON DELETE CASCADE
+);
+
+CREATE TABLE subscriptions (
+  subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('user', 'organization')),
+  subject_id UUID NOT NULL,
+  plan_id UUID NOT NULL,
+  started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY (subject_type, subject_id),
+  FOREIGN KEY (plan_id) REFERENCES plans(id)
+);
+
+CREATE TABLE usage_counters (
+  subject_type VARCHAR(20) NOT NULL,
+  subject_id UUID NOT NULL,
+  key VARCHAR(64) NOT NULL,
+  period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+  used BIGINT DEFAULT 0 NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY (subject_type, subject_id, key, period_start)
+);
+
+INSERT INTO plans (key, name)
+VALUES ('free', 'Free'), ('pro', 'Pro'), ('enterprise', 'Enterprise');
+
+INSERT INTO plan_entitlements (plan_id, key, quota, period)
+SELECT p.id, e.key, e.quota, e.period
+FROM plans p
+JOIN (
+  VALUES
+    ('free', 'api_requests', 1000, 'day'),
+    ('free', 'invitations', 10, 'month'),
+    ('pro', 'api_requests', 100000, 'day'),
+    ('pro', 'invitations', 500, 'month'),
+    ('enterprise', 'api_requests', -1, 'day'),
+    ('enterprise', 'invitations', -1, 'month')
+) AS e(plan, key, quota, period) ON e.plan = p.key;
+
+INSERT INTO permissions (name)
+VALUES ('plans:manage');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'plans:manage'
+WHERE r.name = 'admin';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions
+WHERE name = 'plans:manage';
+
+DROP TABLE usage_counters;
+DROP TABLE subscriptions;
+DROP TABLE plan_entitlements;
+DROP TABLE plans;
+-- +goose StatementEnd
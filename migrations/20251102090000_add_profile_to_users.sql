@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN display_name VARCHAR(100) DEFAULT '' NOT NULL,
+ADD COLUMN locale VARCHAR(35) DEFAULT '' NOT NULL,
+ADD COLUMN timezone VARCHAR(64) DEFAULT '' NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN timezone,
+DROP COLUMN locale,
+DROP COLUMN display_name;
+-- +goose StatementEnd
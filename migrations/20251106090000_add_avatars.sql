@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+  ADD COLUMN avatar_version VARCHAR(32) NOT NULL DEFAULT '';
+
+ALTER TABLE linked_accounts
+  ADD COLUMN picture_url TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE linked_accounts
+  DROP COLUMN picture_url;
+
+ALTER TABLE users
+  DROP COLUMN avatar_version;
+-- +goose StatementEnd
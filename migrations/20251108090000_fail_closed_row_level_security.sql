@@ -0,0 +1,117 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION app_is_system() RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$ SELECT COALESCE(current_setting('app.system', true), '') = 'on' $$;
+
+DROP POLICY organizations_tenant_isolation ON organizations;
+CREATE POLICY organizations_tenant_isolation ON organizations
+USING (
+  app_is_system()
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+  OR EXISTS (
+    SELECT 1
+    FROM memberships m
+    WHERE m.organization_id = organizations.id AND m.user_id = app_user_id()
+  )
+)
+WITH CHECK (
+  app_is_system()
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+);
+
+DROP POLICY memberships_tenant_isolation ON memberships;
+CREATE POLICY memberships_tenant_isolation ON memberships
+USING (
+  app_is_system()
+  OR organization_id = app_org_id()
+  OR user_id = app_user_id()
+);
+
+DROP POLICY invitations_tenant_isolation ON invitations;
+CREATE POLICY invitations_tenant_isolation ON invitations
+USING (
+  app_is_system()
+  OR organization_id = app_org_id()
+);
+
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+USING (
+  app_is_system()
+  OR (subject_type = 'user' AND subject_id = app_user_id())
+  OR (subject_type = 'organization' AND subject_id = app_org_id())
+  OR (
+    subject_type = 'organization' AND EXISTS (
+      SELECT 1
+      FROM memberships m
+      WHERE m.organization_id = subscriptions.subject_id AND m.user_id = app_user_id()
+    )
+  )
+);
+
+ALTER TABLE usage_counters ENABLE ROW LEVEL SECURITY;
+ALTER TABLE usage_counters FORCE ROW LEVEL SECURITY;
+CREATE POLICY usage_counters_tenant_isolation ON usage_counters
+USING (
+  app_is_system()
+  OR (subject_type = 'user' AND subject_id = app_user_id())
+  OR (subject_type = 'organization' AND subject_id = app_org_id())
+  OR (
+    subject_type = 'organization' AND EXISTS (
+      SELECT 1
+      FROM memberships m
+      WHERE m.organization_id = usage_counters.subject_id AND m.user_id = app_user_id()
+    )
+  )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY usage_counters_tenant_isolation ON usage_counters;
+ALTER TABLE usage_counters NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE usage_counters DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY subscriptions_tenant_isolation ON subscriptions;
+ALTER TABLE subscriptions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY invitations_tenant_isolation ON invitations;
+CREATE POLICY invitations_tenant_isolation ON invitations
+USING (
+  app_user_id() IS NULL
+  OR organization_id = app_org_id()
+);
+
+DROP POLICY memberships_tenant_isolation ON memberships;
+CREATE POLICY memberships_tenant_isolation ON memberships
+USING (
+  app_user_id() IS NULL
+  OR organization_id = app_org_id()
+  OR user_id = app_user_id()
+);
+
+DROP POLICY organizations_tenant_isolation ON organizations;
+CREATE POLICY organizations_tenant_isolation ON organizations
+USING (
+  app_user_id() IS NULL
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+  OR EXISTS (
+    SELECT 1
+    FROM memberships m
+    WHERE m.organization_id = organizations.id AND m.user_id = app_user_id()
+  )
+)
+WITH CHECK (
+  app_user_id() IS NULL
+  OR id = app_org_id()
+  OR created_by = app_user_id()
+);
+
+DROP FUNCTION app_is_system();
+-- +goose StatementEnd
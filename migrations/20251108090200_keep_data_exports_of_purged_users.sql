@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data_exports
+ALTER COLUMN user_id DROP NOT NULL,
+DROP CONSTRAINT data_exports_user_id_fkey,
+ADD CONSTRAINT data_exports_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM data_exports
+WHERE user_id IS NULL;
+
+ALTER TABLE data_exports
+DROP CONSTRAINT data_exports_user_id_fkey,
+ADD CONSTRAINT data_exports_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+ALTER COLUMN user_id SET NOT NULL;
+-- +goose StatementEnd
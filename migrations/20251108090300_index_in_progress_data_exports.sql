@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE UNIQUE INDEX data_exports_in_progress_idx ON data_exports (user_id)
+WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX data_exports_in_progress_idx;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+ADD COLUMN previous_value UUID,
+ADD COLUMN rotated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX refresh_tokens_previous_value_idx ON refresh_tokens (previous_value);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX refresh_tokens_previous_value_idx;
+
+ALTER TABLE refresh_tokens
+DROP COLUMN rotated_at,
+DROP COLUMN previous_value;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP POLICY invitations_tenant_isolation ON invitations;
+CREATE POLICY invitations_tenant_isolation ON invitations
+USING (
+  app_is_system()
+  OR organization_id = app_org_id()
+  OR EXISTS (
+    SELECT 1
+    FROM memberships m
+    WHERE m.organization_id = invitations.organization_id AND m.user_id = app_user_id()
+  )
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP POLICY invitations_tenant_isolation ON invitations;
+CREATE POLICY invitations_tenant_isolation ON invitations
+USING (
+  app_is_system()
+  OR organization_id = app_org_id()
+);
+-- +goose StatementEnd